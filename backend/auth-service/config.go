@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yourusername/ecommerce/pkg/config"
+)
+
+// Config holds the auth-service settings; see config.Load for the sources.
+type Config struct {
+	HTTP  config.HTTP  `yaml:"http"`
+	Mongo config.Mongo `yaml:"mongo"`
+	Log   config.Log   `yaml:"log"`
+
+	UsersCollection string `yaml:"users_collection" env:"MONGODB_USERS_COLLECTION" flag:"users-collection" default:"users" required:"true" usage:"collection holding the users"`
+
+	// JWT settings, matching the Kong consumer credentials in scripts/generate-token.js
+	JWTKey    string        `yaml:"jwt_key" env:"JWT_KEY" flag:"jwt-key" default:"user-key" required:"true" usage:"iss/kid claim Kong looks the consumer up by"`
+	JWTSecret string        `yaml:"jwt_secret" env:"JWT_SECRET" flag:"jwt-secret" required:"true" secret:"true" usage:"HS256 signing secret"`
+	JWTTTL    time.Duration `yaml:"jwt_ttl" env:"JWT_TTL" flag:"jwt-ttl" default:"24h" usage:"lifetime of issued tokens"`
+
+	ShutdownDelay   time.Duration `yaml:"shutdown_delay" env:"SHUTDOWN_DELAY" flag:"shutdown-delay" default:"5s" usage:"how long /readyz fails before the listener closes"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" flag:"shutdown-timeout" default:"20s" usage:"how long in-flight requests may take to finish"`
+}
+
+func (c *Config) Validate() error {
+	if c.JWTTTL <= 0 {
+		return fmt.Errorf("JWT_TTL must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/ecommerce/auth-service/internal/domain"
+)
+
+type MockAuthUseCase struct {
+	mock.Mock
+}
+
+func (m *MockAuthUseCase) Register(ctx context.Context, email, password string) (*domain.User, error) {
+	args := m.Called(ctx, email, password)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockAuthUseCase) Login(ctx context.Context, email, password string) (*domain.Token, error) {
+	args := m.Called(ctx, email, password)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Token), args.Error(1)
+}
+
+func newTestRouter(useCase domain.AuthUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewAuthHandler(router, useCase)
+	return router
+}
+
+func TestRegister(t *testing.T) {
+	mockUseCase := new(MockAuthUseCase)
+	router := newTestRouter(mockUseCase)
+
+	t.Run("Success", func(t *testing.T) {
+		user := &domain.User{Email: "test@example.com", PasswordHash: "hash", Role: domain.RoleUser}
+		mockUseCase.On("Register", mock.Anything, "test@example.com", "password123").Return(user, nil).Once()
+
+		req := httptest.NewRequest("POST", "/api/v1/auth/register", bytes.NewBufferString(`{"email":"test@example.com","password":"password123"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.NotContains(t, rr.Body.String(), "hash")
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Short Password", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/v1/auth/register", bytes.NewBufferString(`{"email":"test@example.com","password":"short"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Duplicate Email", func(t *testing.T) {
+		mockUseCase.On("Register", mock.Anything, "taken@example.com", "password123").Return(nil, domain.ErrEmailTaken).Once()
+
+		req := httptest.NewRequest("POST", "/api/v1/auth/register", bytes.NewBufferString(`{"email":"taken@example.com","password":"password123"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+}
+
+func TestLogin(t *testing.T) {
+	mockUseCase := new(MockAuthUseCase)
+	router := newTestRouter(mockUseCase)
+
+	t.Run("Success", func(t *testing.T) {
+		token := &domain.Token{AccessToken: "signed", TokenType: "Bearer"}
+		mockUseCase.On("Login", mock.Anything, "test@example.com", "password123").Return(token, nil).Once()
+
+		req := httptest.NewRequest("POST", "/api/v1/auth/login", bytes.NewBufferString(`{"email":"test@example.com","password":"password123"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var response domain.Token
+		err := json.Unmarshal(rr.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "signed", response.AccessToken)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Credentials", func(t *testing.T) {
+		mockUseCase.On("Login", mock.Anything, "test@example.com", "wrong").Return(nil, domain.ErrInvalidCredentials).Once()
+
+		req := httptest.NewRequest("POST", "/api/v1/auth/login", bytes.NewBufferString(`{"email":"test@example.com","password":"wrong"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,18 @@
+package mock
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/ecommerce/auth-service/internal/domain"
+)
+
+type MockTokenIssuer struct {
+	mock.Mock
+}
+
+func (m *MockTokenIssuer) Issue(user *domain.User) (*domain.Token, error) {
+	args := m.Called(user)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Token), args.Error(1)
+}
@@ -0,0 +1,64 @@
+package token
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/yourusername/ecommerce/auth-service/internal/domain"
+)
+
+// Claims mirror what scripts/generate-token.js produces for Kong: iss and kid
+// both carry the Kong consumer key, and the token is signed with its secret.
+type Claims struct {
+	Kid   string `json:"kid"`
+	Email string `json:"email"`
+	Role  string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+type jwtIssuer struct {
+	key    string
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+func NewJWTIssuer(key, secret string, ttl time.Duration) domain.TokenIssuer {
+	return &jwtIssuer{
+		key:    key,
+		secret: []byte(secret),
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+func (i *jwtIssuer) Issue(user *domain.User) (*domain.Token, error) {
+	now := i.now()
+	expiresAt := now.Add(i.ttl)
+
+	claims := Claims{
+		Kid:   i.key,
+		Email: user.Email,
+		Role:  user.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    i.key,
+			Subject:   user.ID.Hex(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	t.Header["kid"] = i.key
+
+	signed, err := t.SignedString(i.secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Token{
+		AccessToken: signed,
+		TokenType:   "Bearer",
+		ExpiresAt:   expiresAt,
+	}, nil
+}
@@ -0,0 +1,34 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/ecommerce/auth-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestIssue(t *testing.T) {
+	issuer := NewJWTIssuer("user-key", "user-secret", time.Hour)
+	user := &domain.User{ID: primitive.NewObjectID(), Email: "test@example.com", Role: domain.RoleUser}
+
+	token, err := issuer.Issue(user)
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer", token.TokenType)
+
+	var claims Claims
+	parsed, err := jwt.ParseWithClaims(token.AccessToken, &claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte("user-secret"), nil
+	}, jwt.WithValidMethods([]string{"HS256"}))
+
+	assert.NoError(t, err)
+	assert.True(t, parsed.Valid)
+	assert.Equal(t, "user-key", parsed.Header["kid"])
+	assert.Equal(t, "user-key", claims.Issuer)
+	assert.Equal(t, "user-key", claims.Kid)
+	assert.Equal(t, user.ID.Hex(), claims.Subject)
+	assert.Equal(t, domain.RoleUser, claims.Role)
+	assert.WithinDuration(t, token.ExpiresAt, claims.ExpiresAt.Time, time.Second)
+}
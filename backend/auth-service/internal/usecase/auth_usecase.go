@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/yourusername/ecommerce/auth-service/internal/domain"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// dummyPasswordHash is compared against when no user has the email, so a
+// failed login takes as long for unknown emails as for wrong passwords. It
+// uses bcrypt.DefaultCost, the cost real hashes are made with.
+const dummyPasswordHash = "$2a$10$GY6eUtvvRoChWcXGlOlhMOti7rxdKSR.fibhC1Ejey6O7pXha8CSO"
+
+type authUseCase struct {
+	userRepo   domain.UserRepository
+	issuer     domain.TokenIssuer
+	bcryptCost int
+}
+
+func NewAuthUseCase(userRepo domain.UserRepository, issuer domain.TokenIssuer) domain.AuthUseCase {
+	return &authUseCase{
+		userRepo:   userRepo,
+		issuer:     issuer,
+		bcryptCost: bcrypt.DefaultCost,
+	}
+}
+
+func (u *authUseCase) Register(ctx context.Context, email, password string) (*domain.User, error) {
+	user, err := u.register(ctx, normalizeEmail(email), password)
+	registrations.WithLabelValues(result(err)).Inc()
+	return user, err
+}
+
+func (u *authUseCase) register(ctx context.Context, email, password string) (*domain.User, error) {
+	existing, err := u.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, domain.ErrEmailTaken
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), u.bcryptCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &domain.User{
+		Email:        email,
+		PasswordHash: string(hash),
+		Role:         domain.RoleUser,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+	if err := u.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (u *authUseCase) Login(ctx context.Context, email, password string) (*domain.Token, error) {
+	token, err := u.login(ctx, normalizeEmail(email), password)
+	logins.WithLabelValues(result(err)).Inc()
+	return token, err
+}
+
+func (u *authUseCase) login(ctx context.Context, email, password string) (*domain.Token, error) {
+	user, err := u.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		_ = bcrypt.CompareHashAndPassword([]byte(dummyPasswordHash), []byte(password))
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	return u.issuer.Issue(user)
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/ecommerce/auth-service/internal/domain"
+	mockRepo "github.com/yourusername/ecommerce/auth-service/internal/repository/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newTestUseCase(repo domain.UserRepository, issuer domain.TokenIssuer) *authUseCase {
+	return &authUseCase{
+		userRepo:   repo,
+		issuer:     issuer,
+		bcryptCost: bcrypt.MinCost,
+	}
+}
+
+func TestRegister(t *testing.T) {
+	mockRepo := new(mockRepo.MockUserRepository)
+	useCase := newTestUseCase(mockRepo, nil)
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo.On("GetByEmail", mock.Anything, "test@example.com").Return(nil, nil).Once()
+		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+			return u.Email == "test@example.com" &&
+				u.Role == domain.RoleUser &&
+				bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte("password123")) == nil &&
+				!u.CreatedAt.IsZero()
+		})).Return(nil).Once()
+
+		user, err := useCase.Register(context.Background(), " Test@Example.com ", "password123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "test@example.com", user.Email)
+		assert.NotEqual(t, "password123", user.PasswordHash)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Duplicate Email", func(t *testing.T) {
+		mockRepo.On("GetByEmail", mock.Anything, "taken@example.com").Return(&domain.User{Email: "taken@example.com"}, nil).Once()
+
+		user, err := useCase.Register(context.Background(), "taken@example.com", "password123")
+
+		assert.ErrorIs(t, err, domain.ErrEmailTaken)
+		assert.Nil(t, user)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Duplicate Email Race", func(t *testing.T) {
+		mockRepo.On("GetByEmail", mock.Anything, "race@example.com").Return(nil, nil).Once()
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(domain.ErrEmailTaken).Once()
+
+		user, err := useCase.Register(context.Background(), "race@example.com", "password123")
+
+		assert.ErrorIs(t, err, domain.ErrEmailTaken)
+		assert.Nil(t, user)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestLogin(t *testing.T) {
+	mockIssuer := new(mockRepo.MockTokenIssuer)
+	mockRepo := new(mockRepo.MockUserRepository)
+	useCase := newTestUseCase(mockRepo, mockIssuer)
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	user := &domain.User{Email: "test@example.com", PasswordHash: string(hash), Role: domain.RoleUser}
+
+	t.Run("Success", func(t *testing.T) {
+		expected := &domain.Token{AccessToken: "signed", TokenType: "Bearer"}
+		mockRepo.On("GetByEmail", mock.Anything, "test@example.com").Return(user, nil).Once()
+		mockIssuer.On("Issue", user).Return(expected, nil).Once()
+
+		token, err := useCase.Login(context.Background(), "test@example.com", "password123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, token)
+		mockRepo.AssertExpectations(t)
+		mockIssuer.AssertExpectations(t)
+	})
+
+	t.Run("Wrong Password", func(t *testing.T) {
+		mockRepo.On("GetByEmail", mock.Anything, "test@example.com").Return(user, nil).Once()
+		failed := testutil.ToFloat64(logins.WithLabelValues("invalid_credentials"))
+
+		token, err := useCase.Login(context.Background(), "test@example.com", "wrong-password")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+		assert.Nil(t, token)
+		assert.Equal(t, failed+1, testutil.ToFloat64(logins.WithLabelValues("invalid_credentials")))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Email", func(t *testing.T) {
+		mockRepo.On("GetByEmail", mock.Anything, "nobody@example.com").Return(nil, nil).Once()
+
+		token, err := useCase.Login(context.Background(), "nobody@example.com", "password123")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+		assert.Nil(t, token)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Email Still Hashes", func(t *testing.T) {
+		// A malformed hash would fail fast and give unknown emails away.
+		cost, err := bcrypt.Cost([]byte(dummyPasswordHash))
+
+		assert.NoError(t, err)
+		assert.Equal(t, bcrypt.DefaultCost, cost)
+	})
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/yourusername/ecommerce/auth-service/internal/domain"
+)
+
+var (
+	registrations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_registrations_total",
+		Help: "Registration attempts, by result.",
+	}, []string{"result"})
+
+	logins = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_logins_total",
+		Help: "Login attempts, by result.",
+	}, []string{"result"})
+)
+
+// result names the outcome of an attempt for the counters above.
+func result(err error) string {
+	switch {
+	case err == nil:
+		return "success"
+	case errors.Is(err, domain.ErrEmailTaken):
+		return "email_taken"
+	case errors.Is(err, domain.ErrInvalidCredentials):
+		return "invalid_credentials"
+	}
+	return "error"
+}
@@ -1,36 +1,145 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"log"
+	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
+	"github.com/yourusername/ecommerce/pkg/config"
+	"github.com/yourusername/ecommerce/pkg/health"
+	"github.com/yourusername/ecommerce/pkg/logging"
+	"github.com/yourusername/ecommerce/pkg/metrics"
+	"github.com/yourusername/ecommerce/pkg/tracing"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+
+	authHttp "github.com/yourusername/ecommerce/auth-service/internal/delivery/http"
+	userRepo "github.com/yourusername/ecommerce/auth-service/internal/repository/mongo"
+	"github.com/yourusername/ecommerce/auth-service/internal/token"
+	"github.com/yourusername/ecommerce/auth-service/internal/usecase"
 )
 
 func main() {
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found")
+	cfg := Config{HTTP: config.HTTP{Port: "8081"}}
+	if err := config.Load(&cfg, os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		log.Fatal(err)
+	}
+	if _, err := logging.Setup(cfg.Log.Level, cfg.Log.Format); err != nil {
+		log.Fatal(err)
 	}
+	slog.Info("effective config", "config", config.LogValue(&cfg))
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8081"
+	if err := run(cfg); err != nil {
+		logging.Fatal(err)
 	}
+}
 
-	r := gin.Default()
+// run starts the service and blocks until it is stopped. Failures are
+// returned rather than exiting on the spot, so the deferred cleanup still
+// runs.
+func run(cfg Config) error {
+	// SIGTERM (docker stop, rolling deploys) and SIGINT start a graceful
+	// shutdown.
+	stopCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	// Health check
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status": "ok",
-		})
-	})
+	// Tracing
+	shutdownTracing, err := tracing.Setup(context.Background(), "auth-service")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("tracing shutdown", "error", err)
+		}
+	}()
 
-	// Routes will be added here
-	
-	log.Printf("Auth Service starting on port %s", port)
-	if err := r.Run(":" + port); err != nil {
-		log.Fatal(err)
+	// MongoDB connection
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Mongo.ConnectTimeout)
+	defer cancel()
+
+	monitor := tracing.MongoMonitor(metrics.MongoMonitor())
+	client, err := mongo.Connect(ctx, cfg.Mongo.ClientOptions().SetMonitor(monitor))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := client.Disconnect(ctx); err != nil {
+			slog.Error("mongo disconnect", "error", err)
+		}
+	}()
+
+	collection := client.Database(cfg.Mongo.Database).Collection(cfg.UsersCollection)
+	if err := userRepo.EnsureIndexes(ctx, collection); err != nil {
+		return err
+	}
+
+	// Initialize layers
+	userRepo := userRepo.NewMongoUserRepository(collection)
+	issuer := token.NewJWTIssuer(cfg.JWTKey, cfg.JWTSecret, cfg.JWTTTL)
+	authUseCase := usecase.NewAuthUseCase(userRepo, issuer)
+
+	// gin's own logger is replaced by the structured access log.
+	r := gin.New()
+	r.Use(gin.Recovery())
+	r.Use(otelgin.Middleware("auth-service"))
+	r.Use(authHttp.LoggingMiddleware(cfg.Log.AccessLog))
+	r.Use(authHttp.MetricsMiddleware())
+
+	// Prometheus metrics
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	// Liveness and readiness probes; /health is kept for existing callers.
+	probes := health.NewChecker(2*time.Second, 5*time.Second)
+	probes.Add("mongodb", health.Mongo(client))
+	r.GET("/livez", gin.WrapH(probes.Livez()))
+	r.GET("/health", gin.WrapH(probes.Livez()))
+	r.GET("/readyz", gin.WrapH(probes.Readyz()))
+
+	// Register routes
+	authHttp.NewAuthHandler(r, authUseCase)
+
+	// Start server
+	srv := cfg.HTTP.Server(r)
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("Auth Service starting", "port", cfg.HTTP.Port)
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		// ListenAndServe only returns before Shutdown when it cannot serve
+		// at all, e.g. the port is taken.
+		return err
+	case <-stopCtx.Done():
+		slog.Info("shutting down")
+	}
+
+	// Fail readiness first and keep serving for a moment, so Kong and other
+	// probers take the instance out of rotation before connections close.
+	probes.SetShuttingDown()
+	time.Sleep(cfg.ShutdownDelay)
+
+	// Stop accepting connections and let in-flight requests finish.
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancelShutdown()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("http shutdown", "error", err)
 	}
+	return nil
 }
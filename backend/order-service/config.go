@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/yourusername/ecommerce/pkg/config"
+)
+
+// Config holds the order-service settings; see config.Load for the sources.
+type Config struct {
+	HTTP  config.HTTP  `yaml:"http"`
+	Mongo config.Mongo `yaml:"mongo"`
+	Log   config.Log   `yaml:"log"`
+
+	MigrateOnStart bool `yaml:"migrate_on_start" env:"MIGRATE_ON_START" flag:"migrate-on-start" default:"true" usage:"apply pending schema migrations before serving"`
+
+	OrdersCollection string `yaml:"orders_collection" env:"MONGODB_ORDERS_COLLECTION" flag:"orders-collection" default:"orders" required:"true" usage:"collection holding the orders"`
+
+	ProductServiceURL     string        `yaml:"product_service_url" env:"PRODUCT_SERVICE_URL" flag:"product-service-url" default:"http://localhost:8082" required:"true" usage:"base URL of product-service"`
+	ProductServiceTimeout time.Duration `yaml:"product_service_timeout" env:"PRODUCT_SERVICE_TIMEOUT" flag:"product-service-timeout" default:"5s" usage:"timeout of each call to product-service"`
+
+	JWTSecret   string `yaml:"jwt_secret" env:"JWT_SECRET" flag:"jwt-secret" required:"true" secret:"true" usage:"HS256 secret shared with auth-service and product-service"`
+	JWTJWKSFile string `yaml:"jwt_jwks_file" env:"JWT_JWKS_FILE" flag:"jwt-jwks-file" usage:"JWKS file with the RS256 public keys"`
+
+	ShutdownDelay   time.Duration `yaml:"shutdown_delay" env:"SHUTDOWN_DELAY" flag:"shutdown-delay" default:"5s" usage:"how long /readyz fails before the listener closes"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" flag:"shutdown-timeout" default:"20s" usage:"how long in-flight requests may take to finish"`
+}
+
+func (c *Config) Validate() error {
+	if u, err := url.Parse(c.ProductServiceURL); err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("PRODUCT_SERVICE_URL %q is not an absolute URL", c.ProductServiceURL)
+	}
+	if c.ProductServiceTimeout <= 0 {
+		return fmt.Errorf("PRODUCT_SERVICE_TIMEOUT must be positive")
+	}
+	// Either key source may be left out, e.g. when every token is RS256.
+	return nil
+}
@@ -0,0 +1,152 @@
+package http
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+	"order-service/internal/domain"
+)
+
+// TokenVerifier validates bearer tokens signed either with a shared HS256
+// secret (tokens from auth-service) or with RS256 keys published in a JWKS file.
+type TokenVerifier struct {
+	hmacSecret []byte
+	rsaKeys    map[string]*rsa.PublicKey
+}
+
+type tokenClaims struct {
+	Role  string   `json:"role"`
+	Roles []string `json:"roles"`
+	jwt.RegisteredClaims
+}
+
+type jwks struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+func NewTokenVerifier(hmacSecret, jwksFile string) (*TokenVerifier, error) {
+	v := &TokenVerifier{
+		hmacSecret: []byte(hmacSecret),
+		rsaKeys:    make(map[string]*rsa.PublicKey),
+	}
+
+	if jwksFile != "" {
+		if err := v.loadJWKS(jwksFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(v.hmacSecret) == 0 && len(v.rsaKeys) == 0 {
+		return nil, errors.New("no JWT verification keys configured")
+	}
+	return v, nil
+}
+
+func (v *TokenVerifier) loadJWKS(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read JWKS file: %w", err)
+	}
+
+	var set jwks
+	if err := json.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("parse JWKS file: %w", err)
+	}
+
+	for _, key := range set.Keys {
+		if key.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+		if err != nil {
+			return fmt.Errorf("decode modulus of key %q: %w", key.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+		if err != nil {
+			return fmt.Errorf("decode exponent of key %q: %w", key.Kid, err)
+		}
+		v.rsaKeys[key.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return nil
+}
+
+func (v *TokenVerifier) keyFunc(token *jwt.Token) (interface{}, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		if len(v.hmacSecret) == 0 {
+			return nil, errors.New("HS256 tokens are not accepted")
+		}
+		return v.hmacSecret, nil
+	case jwt.SigningMethodRS256.Alg():
+		kid, _ := token.Header["kid"].(string)
+		key, ok := v.rsaKeys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		return key, nil
+	}
+	return nil, fmt.Errorf("unexpected signing method %q", token.Method.Alg())
+}
+
+func (v *TokenVerifier) Verify(tokenString string) (*domain.Principal, error) {
+	var claims tokenClaims
+	_, err := jwt.ParseWithClaims(tokenString, &claims, v.keyFunc,
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+
+	roles := claims.Roles
+	if claims.Role != "" {
+		roles = append(roles, claims.Role)
+	}
+	return &domain.Principal{Subject: claims.Subject, Roles: roles}, nil
+}
+
+// AuthMiddleware rejects requests without a valid bearer token and stores the
+// authenticated principal in the request context.
+func AuthMiddleware(verifier *TokenVerifier) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			tokenString, ok := strings.CutPrefix(header, "Bearer ")
+			if !ok || tokenString == "" {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeProblem(w, r, http.StatusUnauthorized, "missing_token", "a bearer token is required")
+				return
+			}
+
+			principal, err := verifier.Verify(tokenString)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				writeProblem(w, r, http.StatusUnauthorized, "invalid_token", "the bearer token is invalid or expired")
+				return
+			}
+
+			ctx := domain.ContextWithPrincipal(r.Context(), principal)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package http
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"order-service/internal/domain"
+)
+
+func newProtectedRouter(verifier *TokenVerifier) (*mux.Router, *domain.Principal) {
+	var seen domain.Principal
+	router := mux.NewRouter()
+	router.Use(AuthMiddleware(verifier))
+	router.HandleFunc("/api/v1/orders", func(w http.ResponseWriter, r *http.Request) {
+		principal, err := domain.PrincipalFromContext(r.Context())
+		if err == nil {
+			seen = *principal
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	return router, &seen
+}
+
+func signHS256(t *testing.T, secret string, claims jwt.MapClaims) string {
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	require.NoError(t, err)
+	return signed
+}
+
+func writeJWKS(t *testing.T, kid string, key *rsa.PublicKey) string {
+	set := map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": kid,
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	}
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	verifier, err := NewTokenVerifier("user-secret", writeJWKS(t, "rsa-1", &rsaKey.PublicKey))
+	require.NoError(t, err)
+	router, seen := newProtectedRouter(verifier)
+
+	exp := time.Now().Add(time.Hour).Unix()
+
+	serve := func(authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/v1/orders", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("HS256", func(t *testing.T) {
+		token := signHS256(t, "user-secret", jwt.MapClaims{"sub": "123", "role": "user", "exp": exp})
+
+		rr := serve("Bearer " + token)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "123", seen.Subject)
+		assert.False(t, seen.IsAdmin())
+	})
+
+	t.Run("RS256 From JWKS", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "admin-1", "roles": []string{"admin"}, "exp": exp})
+		token.Header["kid"] = "rsa-1"
+		signed, err := token.SignedString(rsaKey)
+		require.NoError(t, err)
+
+		rr := serve("Bearer " + signed)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "admin-1", seen.Subject)
+		assert.True(t, seen.IsAdmin())
+	})
+
+	t.Run("Missing Token", func(t *testing.T) {
+		rr := serve("")
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, "Bearer", rr.Header().Get("WWW-Authenticate"))
+	})
+
+	t.Run("Wrong Secret", func(t *testing.T) {
+		token := signHS256(t, "other-secret", jwt.MapClaims{"sub": "123", "exp": exp})
+
+		assert.Equal(t, http.StatusUnauthorized, serve("Bearer "+token).Code)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		token := signHS256(t, "user-secret", jwt.MapClaims{"sub": "123", "exp": time.Now().Add(-time.Minute).Unix()})
+
+		assert.Equal(t, http.StatusUnauthorized, serve("Bearer "+token).Code)
+	})
+
+	t.Run("No Expiry", func(t *testing.T) {
+		token := signHS256(t, "user-secret", jwt.MapClaims{"sub": "123"})
+
+		assert.Equal(t, http.StatusUnauthorized, serve("Bearer "+token).Code)
+	})
+
+	t.Run("No Subject", func(t *testing.T) {
+		token := signHS256(t, "user-secret", jwt.MapClaims{"exp": exp})
+
+		assert.Equal(t, http.StatusUnauthorized, serve("Bearer "+token).Code)
+	})
+
+	t.Run("Unknown Key ID", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "123", "exp": exp})
+		token.Header["kid"] = "rsa-2"
+		signed, err := token.SignedString(rsaKey)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusUnauthorized, serve("Bearer "+signed).Code)
+	})
+}
+
+func TestNewTokenVerifierRequiresKeys(t *testing.T) {
+	_, err := NewTokenVerifier("", "")
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"order-service/internal/domain"
+)
+
+// setETag exposes the order version as a strong entity tag, e.g. "3".
+func setETag(w http.ResponseWriter, order *domain.Order) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(order.Version, 10)))
+}
+
+// ifMatchVersion returns the version named by the If-Match header, or 0 when
+// the header is absent or "*" and the write should not be conditional.
+func ifMatchVersion(r *http.Request) (int64, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	tag, err := strconv.Unquote(header)
+	if err != nil || strings.HasPrefix(header, "W/") {
+		return 0, fmt.Errorf("If-Match must be a single strong ETag such as \"3\"")
+	}
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("If-Match %s does not name an order version", header)
+	}
+	return version, nil
+}
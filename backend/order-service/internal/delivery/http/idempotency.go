@@ -0,0 +1,132 @@
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"order-service/internal/domain"
+)
+
+const (
+	idempotencyKeyHeader = "Idempotency-Key"
+	maxIdempotencyKeyLen = 255
+)
+
+// replayedHeaders are the response headers stored with an idempotency record
+// and sent again on replay.
+var replayedHeaders = []string{"Content-Type", "ETag"}
+
+// idempotent makes next safe to retry when the client sends an
+// Idempotency-Key header. The first request with a key runs normally and its
+// response is stored; retries with the same body get that response back,
+// retries with a different body get 422. Server errors release the key so a
+// later retry runs again.
+func (h *OrderHandler) idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+		if key == "" || h.idempotency == nil {
+			next(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLen {
+			writeProblem(w, r, http.StatusBadRequest, "invalid_idempotency_key", "Idempotency-Key must be at most 255 characters")
+			return
+		}
+
+		principal, err := domain.PrincipalFromContext(r.Context())
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		body, ok := readBody(w, r)
+		if !ok {
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		record := &domain.IdempotencyRecord{
+			Key:         key,
+			UserID:      principal.Subject,
+			RequestHash: requestHash(r, body),
+			CreatedAt:   time.Now(),
+		}
+		existing, err := h.idempotency.Reserve(r.Context(), record)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if existing != nil {
+			replay(w, r, record, existing)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(rec, r)
+
+		if rec.status >= http.StatusInternalServerError {
+			if err := h.idempotency.Release(r.Context(), record.UserID, record.Key); err != nil {
+				slog.ErrorContext(r.Context(), "release idempotency key", "key", key, "error", err)
+			}
+			return
+		}
+
+		record.StatusCode = rec.status
+		record.Body = rec.body.Bytes()
+		record.Header = make(map[string]string, len(replayedHeaders))
+		for _, name := range replayedHeaders {
+			if value := w.Header().Get(name); value != "" {
+				record.Header[name] = value
+			}
+		}
+		if err := h.idempotency.Complete(r.Context(), record); err != nil {
+			slog.ErrorContext(r.Context(), "store idempotent response", "key", key, "error", err)
+		}
+	}
+}
+
+func replay(w http.ResponseWriter, r *http.Request, record, existing *domain.IdempotencyRecord) {
+	switch {
+	case existing.RequestHash != record.RequestHash:
+		writeError(w, r, domain.ErrIdempotencyKeyReused)
+	case !existing.Completed:
+		writeError(w, r, domain.ErrIdempotencyInProgress)
+	default:
+		for name, value := range existing.Header {
+			w.Header().Set(name, value)
+		}
+		w.Header().Set("Idempotent-Replayed", "true")
+		w.WriteHeader(existing.StatusCode)
+		w.Write(existing.Body)
+	}
+}
+
+func requestHash(r *http.Request, body []byte) string {
+	sum := sha256.New()
+	io.WriteString(sum, r.Method+" "+r.URL.Path+"\n")
+	sum.Write(body)
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// responseRecorder passes a response through while keeping a copy of the
+// status and body.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
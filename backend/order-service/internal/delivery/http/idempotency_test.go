@@ -0,0 +1,114 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"order-service/internal/domain"
+	"order-service/internal/repository/memory"
+)
+
+func TestIdempotentCreateOrder(t *testing.T) {
+	mockUseCase := new(MockOrderUseCase)
+	store := memory.NewIdempotencyStore()
+	router := mux.NewRouter()
+	NewOrderHandler(router, mockUseCase, store)
+
+	newRequest := func(subject, key, body string) *http.Request {
+		req := httptest.NewRequest("POST", "/api/v1/orders", bytes.NewBufferString(body))
+		req.Header.Set(idempotencyKeyHeader, key)
+		return req.WithContext(domain.ContextWithPrincipal(req.Context(), &domain.Principal{Subject: subject}))
+	}
+	body := `{"items":[{"product_id":"456","quantity":2}]}`
+
+	t.Run("Replays First Response", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockUseCase.On("CreateOrder", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			order := args.Get(1).(*domain.Order)
+			order.ID = id
+			order.Version = 1
+		}).Return(nil).Once()
+
+		first := httptest.NewRecorder()
+		router.ServeHTTP(first, newRequest("123", "key-1", body))
+		retry := httptest.NewRecorder()
+		router.ServeHTTP(retry, newRequest("123", "key-1", body))
+
+		assert.Equal(t, http.StatusCreated, first.Code)
+		assert.Equal(t, http.StatusCreated, retry.Code)
+		assert.Equal(t, first.Body.String(), retry.Body.String())
+		assert.Equal(t, `"1"`, retry.Header().Get("ETag"))
+		assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+		assert.Empty(t, first.Header().Get("Idempotent-Replayed"))
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Different Body", func(t *testing.T) {
+		mockUseCase.On("CreateOrder", mock.Anything, mock.Anything).Return(nil).Once()
+
+		router.ServeHTTP(httptest.NewRecorder(), newRequest("123", "key-2", body))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, newRequest("123", "key-2", `{"items":[{"product_id":"789","quantity":1}]}`))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+		assert.Equal(t, "idempotency_key_reused", decodeProblem(t, rr).Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Keys Are Scoped To User", func(t *testing.T) {
+		mockUseCase.On("CreateOrder", mock.Anything, mock.Anything).Return(nil).Twice()
+
+		router.ServeHTTP(httptest.NewRecorder(), newRequest("123", "key-3", body))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, newRequest("456", "key-3", body))
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Empty(t, rr.Header().Get("Idempotent-Replayed"))
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("In Progress", func(t *testing.T) {
+		req := newRequest("123", "key-4", body)
+		_, err := store.Reserve(req.Context(), &domain.IdempotencyRecord{
+			Key:         "key-4",
+			UserID:      "123",
+			RequestHash: requestHash(req, []byte(body)),
+		})
+		assert.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		assert.Equal(t, "idempotency_request_in_progress", decodeProblem(t, rr).Code)
+	})
+
+	t.Run("Server Error Releases Key", func(t *testing.T) {
+		mockUseCase.On("CreateOrder", mock.Anything, mock.Anything).Return(assert.AnError).Once()
+		mockUseCase.On("CreateOrder", mock.Anything, mock.Anything).Return(nil).Once()
+
+		first := httptest.NewRecorder()
+		router.ServeHTTP(first, newRequest("123", "key-5", body))
+		retry := httptest.NewRecorder()
+		router.ServeHTTP(retry, newRequest("123", "key-5", body))
+
+		assert.Equal(t, http.StatusInternalServerError, first.Code)
+		assert.Equal(t, http.StatusCreated, retry.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Key Too Long", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, newRequest("123", strings.Repeat("k", 256), body))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, "invalid_idempotency_key", decodeProblem(t, rr).Code)
+	})
+}
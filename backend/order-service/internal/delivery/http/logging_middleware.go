@@ -0,0 +1,35 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/yourusername/ecommerce/pkg/logging"
+)
+
+// LoggingMiddleware takes the request ID from X-Request-ID (or makes one up),
+// echoes it in the response and puts it in the request context so every log
+// line of the request, and every call to product-service, carries it. With
+// accessLog it also logs each request once it finished.
+func LoggingMiddleware(accessLog bool) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := logging.RequestIDFromHeader(r.Header)
+			w.Header().Set(logging.HeaderRequestID, id)
+			r = r.WithContext(logging.WithRequestID(r.Context(), id))
+
+			if !accessLog {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+			r, matched := withMatchedRoute(r)
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(sw, r)
+
+			logging.Access(r.Context(), r.Method, r.URL.Path, routeTemplate(r, matched), sw.status, time.Since(start))
+		})
+	}
+}
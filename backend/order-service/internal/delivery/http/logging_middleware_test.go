@@ -0,0 +1,59 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/ecommerce/pkg/logging"
+)
+
+func TestLoggingMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	handler, err := logging.NewHandler(&buf, "info", logging.FormatJSON)
+	require.NoError(t, err)
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(handler))
+
+	var seen string
+	router := mux.NewRouter()
+	router.Use(LoggingMiddleware(true))
+	router.HandleFunc("/api/v1/orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+		seen = logging.RequestID(r.Context())
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	t.Run("Keeps Incoming ID", func(t *testing.T) {
+		buf.Reset()
+		req := httptest.NewRequest("GET", "/api/v1/orders/507f1f77bcf86cd799439011", nil)
+		req.Header.Set(logging.HeaderRequestID, "kong-1")
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, "kong-1", seen)
+		assert.Equal(t, "kong-1", rr.Header().Get(logging.HeaderRequestID))
+
+		var line map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+		assert.Equal(t, "request", line["msg"])
+		assert.Equal(t, "kong-1", line["request_id"])
+		assert.Equal(t, "/api/v1/orders/{id}", line["route"])
+		assert.Equal(t, float64(http.StatusTeapot), line["status"])
+	})
+
+	t.Run("Generates ID", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/orders/1", nil))
+
+		assert.Len(t, seen, 32)
+		assert.Equal(t, seen, rr.Header().Get(logging.HeaderRequestID))
+	})
+}
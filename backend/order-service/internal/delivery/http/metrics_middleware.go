@@ -0,0 +1,32 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/yourusername/ecommerce/pkg/metrics"
+)
+
+// MetricsMiddleware records request count, status and latency per route
+// template, so /api/v1/orders/{id} is one series however many orders exist.
+func MetricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		r, matched := withMatchedRoute(r)
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r)
+
+		metrics.ObserveHTTP(r.Method, routeTemplate(r, matched), sw.status, time.Since(start))
+	})
+}
+
+// statusWriter remembers the status code written through it.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
@@ -2,78 +2,105 @@ package http
 
 import (
 	"encoding/json"
+	"fmt"
+	"mime"
 	"net/http"
+	"sort"
+	"strconv"
 
 	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"order-service/internal/domain"
 )
 
+const mergePatchContentType = "application/merge-patch+json"
+
 type OrderHandler struct {
 	orderUseCase domain.OrderUseCase
+	idempotency  domain.IdempotencyStore
 }
 
-func NewOrderHandler(r *mux.Router, orderUseCase domain.OrderUseCase) {
+// NewOrderHandler registers the order routes on r. idempotency may be nil, in
+// which case Idempotency-Key headers are ignored.
+func NewOrderHandler(r *mux.Router, orderUseCase domain.OrderUseCase, idempotency domain.IdempotencyStore) {
 	handler := &OrderHandler{
 		orderUseCase: orderUseCase,
+		idempotency:  idempotency,
 	}
 
-	r.HandleFunc("/api/v1/orders", handler.CreateOrder).Methods("POST")
-	r.HandleFunc("/api/v1/orders", handler.GetOrders).Methods("GET")
-	r.HandleFunc("/api/v1/orders/{id}", handler.GetOrder).Methods("GET")
-	r.HandleFunc("/api/v1/orders/{id}", handler.UpdateOrder).Methods("PUT")
-	r.HandleFunc("/api/v1/orders/{id}", handler.DeleteOrder).Methods("DELETE")
+	r.HandleFunc("/api/v1/orders", traced("CreateOrder", handler.idempotent(handler.CreateOrder))).Methods("POST")
+	r.HandleFunc("/api/v1/orders", traced("GetOrders", handler.GetOrders)).Methods("GET")
+	r.HandleFunc("/api/v1/orders/{id}", traced("GetOrder", handler.GetOrder)).Methods("GET")
+	r.HandleFunc("/api/v1/orders/{id}", traced("UpdateOrder", handler.UpdateOrder)).Methods("PUT")
+	r.HandleFunc("/api/v1/orders/{id}", traced("PatchOrder", handler.PatchOrder)).Methods("PATCH")
+	r.HandleFunc("/api/v1/orders/{id}", traced("DeleteOrder", handler.DeleteOrder)).Methods("DELETE")
+	r.HandleFunc("/api/v1/orders/{id}/transitions", traced("TransitionOrder", handler.TransitionOrder)).Methods("POST")
+	r.HandleFunc("/api/v1/orders/{id}/restore", traced("RestoreOrder", handler.RestoreOrder)).Methods("POST")
 }
 
 func (h *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
-	var order domain.Order
-	if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	var req createOrderRequest
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
+	order := req.toOrder()
 	if err := h.orderUseCase.CreateOrder(r.Context(), &order); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
+	setETag(w, &order)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(order)
 }
 
 func (h *OrderHandler) GetOrders(w http.ResponseWriter, r *http.Request) {
-	userID := r.URL.Query().Get("user_id")
-	
-	orders, err := h.orderUseCase.GetOrders(r.Context(), userID)
+	filter, err := parseOrderFilter(r.URL.Query())
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid_query", err.Error())
+		return
+	}
+
+	page, err := h.orderUseCase.GetOrders(r.Context(), filter)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(orders)
+	json.NewEncoder(w).Encode(page)
 }
 
 func (h *OrderHandler) GetOrder(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
 	id, err := primitive.ObjectIDFromHex(params["id"])
 	if err != nil {
-		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, "invalid_order_id", "order ID must be a 24-character hex string")
 		return
 	}
 
-	order, err := h.orderUseCase.GetOrder(r.Context(), id)
+	var includeDeleted bool
+	if v := r.URL.Query().Get("include_deleted"); v != "" {
+		if includeDeleted, err = strconv.ParseBool(v); err != nil {
+			writeProblem(w, r, http.StatusBadRequest, "invalid_query", fmt.Sprintf("invalid include_deleted %q", v))
+			return
+		}
+	}
+
+	order, err := h.orderUseCase.GetOrder(r.Context(), id, includeDeleted)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
 	if order == nil {
-		http.Error(w, "Order not found", http.StatusNotFound)
+		writeError(w, r, domain.ErrOrderNotFound)
 		return
 	}
 
+	setETag(w, order)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(order)
 }
@@ -82,37 +109,164 @@ func (h *OrderHandler) UpdateOrder(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
 	id, err := primitive.ObjectIDFromHex(params["id"])
 	if err != nil {
-		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, "invalid_order_id", "order ID must be a 24-character hex string")
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid_if_match", err.Error())
 		return
 	}
 
-	var order domain.Order
-	if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	var req updateOrderRequest
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
+	order := req.toOrder()
 	order.ID = id
+	order.Version = version
 	if err := h.orderUseCase.UpdateOrder(r.Context(), &order); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
+	setETag(w, &order)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]string{"message": "Order updated successfully"})
 }
 
+// PatchOrder applies a JSON Merge Patch (RFC 7396) and returns the updated order.
+func (h *OrderHandler) PatchOrder(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	id, err := primitive.ObjectIDFromHex(params["id"])
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid_order_id", "order ID must be a 24-character hex string")
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid_if_match", err.Error())
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != mergePatchContentType && mediaType != "application/json" {
+		w.Header().Set("Accept-Patch", mergePatchContentType)
+		writeProblem(w, r, http.StatusUnsupportedMediaType, "unsupported_media_type",
+			"PATCH requests must use "+mergePatchContentType)
+		return
+	}
+
+	body, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(body, &members); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "malformed_request", "a merge patch must be a JSON object")
+		return
+	}
+	var removed []FieldError
+	for name, value := range members {
+		if string(value) == "null" {
+			removed = append(removed, FieldError{Field: name, Message: "cannot be removed"})
+		}
+	}
+	if len(removed) > 0 {
+		sort.Slice(removed, func(i, j int) bool { return removed[i].Field < removed[j].Field })
+		writeValidationProblem(w, r, removed)
+		return
+	}
+
+	var req patchOrderRequest
+	if !decodeBody(w, r, body, &req) {
+		return
+	}
+
+	patch := req.toPatch()
+	patch.Version = version
+	order, err := h.orderUseCase.PatchOrder(r.Context(), id, patch)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	setETag(w, order)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(order)
+}
+
+func (h *OrderHandler) TransitionOrder(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	id, err := primitive.ObjectIDFromHex(params["id"])
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid_order_id", "order ID must be a 24-character hex string")
+		return
+	}
+
+	var req transitionRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	order, err := h.orderUseCase.TransitionOrder(r.Context(), id, req.Status)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	setETag(w, order)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(order)
+}
+
+// RestoreOrder undoes a soft delete and returns the restored order.
+func (h *OrderHandler) RestoreOrder(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	id, err := primitive.ObjectIDFromHex(params["id"])
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid_order_id", "order ID must be a 24-character hex string")
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid_if_match", err.Error())
+		return
+	}
+
+	order, err := h.orderUseCase.RestoreOrder(r.Context(), id, version)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	setETag(w, order)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(order)
+}
+
 func (h *OrderHandler) DeleteOrder(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
 	id, err := primitive.ObjectIDFromHex(params["id"])
 	if err != nil {
-		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, "invalid_order_id", "order ID must be a 24-character hex string")
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid_if_match", err.Error())
 		return
 	}
 
-	if err := h.orderUseCase.DeleteOrder(r.Context(), id); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err := h.orderUseCase.DeleteOrder(r.Context(), id, version); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
@@ -4,16 +4,19 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
-	"order-service/internal/domain"
+	"github.com/yourusername/ecommerce/pkg/money"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"order-service/internal/domain"
 )
 
 type MockOrderUseCase struct {
@@ -25,17 +28,20 @@ func (m *MockOrderUseCase) CreateOrder(ctx context.Context, order *domain.Order)
 	return args.Error(0)
 }
 
-func (m *MockOrderUseCase) GetOrder(ctx context.Context, id primitive.ObjectID) (*domain.Order, error) {
-	args := m.Called(ctx, id)
+func (m *MockOrderUseCase) GetOrder(ctx context.Context, id primitive.ObjectID, includeDeleted bool) (*domain.Order, error) {
+	args := m.Called(ctx, id, includeDeleted)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.Order), args.Error(1)
 }
 
-func (m *MockOrderUseCase) GetOrders(ctx context.Context, userID string) ([]domain.Order, error) {
-	args := m.Called(ctx, userID)
-	return args.Get(0).([]domain.Order), args.Error(1)
+func (m *MockOrderUseCase) GetOrders(ctx context.Context, filter domain.OrderFilter) (*domain.OrderPage, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.OrderPage), args.Error(1)
 }
 
 func (m *MockOrderUseCase) UpdateOrder(ctx context.Context, order *domain.Order) error {
@@ -43,29 +49,58 @@ func (m *MockOrderUseCase) UpdateOrder(ctx context.Context, order *domain.Order)
 	return args.Error(0)
 }
 
-func (m *MockOrderUseCase) DeleteOrder(ctx context.Context, id primitive.ObjectID) error {
-	args := m.Called(ctx, id)
+func (m *MockOrderUseCase) PatchOrder(ctx context.Context, id primitive.ObjectID, patch domain.OrderPatch) (*domain.Order, error) {
+	args := m.Called(ctx, id, patch)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Order), args.Error(1)
+}
+
+func (m *MockOrderUseCase) TransitionOrder(ctx context.Context, id primitive.ObjectID, status domain.OrderStatus) (*domain.Order, error) {
+	args := m.Called(ctx, id, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Order), args.Error(1)
+}
+
+func (m *MockOrderUseCase) RestoreOrder(ctx context.Context, id primitive.ObjectID, version int64) (*domain.Order, error) {
+	args := m.Called(ctx, id, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Order), args.Error(1)
+}
+
+func (m *MockOrderUseCase) DeleteOrder(ctx context.Context, id primitive.ObjectID, version int64) error {
+	args := m.Called(ctx, id, version)
 	return args.Error(0)
 }
 
+func decodeProblem(t *testing.T, rr *httptest.ResponseRecorder) Problem {
+	var problem Problem
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &problem))
+	assert.Equal(t, rr.Code, problem.Status)
+	return problem
+}
+
 func TestCreateOrder(t *testing.T) {
 	mockUseCase := new(MockOrderUseCase)
 	router := mux.NewRouter()
-	NewOrderHandler(router, mockUseCase)
+	NewOrderHandler(router, mockUseCase, nil)
 
 	t.Run("Success", func(t *testing.T) {
-		order := domain.Order{
-			UserID:     "123",
-			ProductID:  "456",
-			Quantity:   2,
-			TotalPrice: 1000,
+		order := createOrderRequest{
+			UserID: "123",
+			Items:  []orderItemRequest{{ProductID: "456", Quantity: 2}},
 		}
 
 		mockUseCase.On("CreateOrder", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
 			return o.UserID == order.UserID &&
-				o.ProductID == order.ProductID &&
-				o.Quantity == order.Quantity &&
-				o.TotalPrice == order.TotalPrice
+				len(o.Items) == 1 &&
+				o.Items[0].ProductID == "456" &&
+				o.Items[0].Quantity == 2
 		})).Return(nil).Once()
 
 		body, _ := json.Marshal(order)
@@ -90,11 +125,9 @@ func TestCreateOrder(t *testing.T) {
 	})
 
 	t.Run("UseCase Error", func(t *testing.T) {
-		order := domain.Order{
-			UserID:     "123",
-			ProductID:  "456",
-			Quantity:   2,
-			TotalPrice: 1000,
+		order := createOrderRequest{
+			UserID: "123",
+			Items:  []orderItemRequest{{ProductID: "456", Quantity: 2}},
 		}
 
 		mockUseCase.On("CreateOrder", mock.Anything, mock.Anything).Return(assert.AnError).Once()
@@ -107,6 +140,72 @@ func TestCreateOrder(t *testing.T) {
 		router.ServeHTTP(rr, req)
 
 		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		assert.Equal(t, problemContentType, rr.Header().Get("Content-Type"))
+		problem := decodeProblem(t, rr)
+		assert.Equal(t, "internal_error", problem.Code)
+		assert.NotContains(t, rr.Body.String(), assert.AnError.Error())
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Validation Errors", func(t *testing.T) {
+		body := `{"items":[{"product_id":"","quantity":-2},{"product_id":"456","quantity":1}]}`
+		req := httptest.NewRequest("POST", "/api/v1/orders", bytes.NewBufferString(body))
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+		problem := decodeProblem(t, rr)
+		assert.Equal(t, "validation_failed", problem.Code)
+		assert.ElementsMatch(t, []FieldError{
+			{Field: "items[0].product_id", Message: "is required"},
+			{Field: "items[0].quantity", Message: "must be at least 1"},
+		}, problem.Errors)
+	})
+
+	t.Run("Empty Items", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/v1/orders", bytes.NewBufferString(`{"items":[]}`))
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+		assert.Equal(t, []FieldError{{Field: "items", Message: "must contain at least 1 item(s)"}}, decodeProblem(t, rr).Errors)
+	})
+
+	t.Run("Unknown Field", func(t *testing.T) {
+		body := `{"items":[{"product_id":"456","quantity":1}],"total_price":0.01}`
+		req := httptest.NewRequest("POST", "/api/v1/orders", bytes.NewBufferString(body))
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, decodeProblem(t, rr).Detail, "total_price")
+	})
+
+	t.Run("Body Too Large", func(t *testing.T) {
+		body := `{"user_id":"` + strings.Repeat("a", maxRequestBodyBytes) + `"}`
+		req := httptest.NewRequest("POST", "/api/v1/orders", bytes.NewBufferString(body))
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	})
+
+	t.Run("Unknown Product", func(t *testing.T) {
+		mockUseCase.On("CreateOrder", mock.Anything, mock.Anything).Return(domain.ErrProductNotFound).Once()
+
+		body := `{"user_id":"123","items":[{"product_id":"999","quantity":1}]}`
+		req := httptest.NewRequest("POST", "/api/v1/orders", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+		assert.Equal(t, "product_not_found", decodeProblem(t, rr).Code)
 		mockUseCase.AssertExpectations(t)
 	})
 }
@@ -114,22 +213,24 @@ func TestCreateOrder(t *testing.T) {
 func TestGetOrder(t *testing.T) {
 	mockUseCase := new(MockOrderUseCase)
 	router := mux.NewRouter()
-	NewOrderHandler(router, mockUseCase)
+	NewOrderHandler(router, mockUseCase, nil)
 
 	t.Run("Success", func(t *testing.T) {
 		id := primitive.NewObjectID()
 		expectedOrder := &domain.Order{
-			ID:         id,
-			UserID:     "123",
-			ProductID:  "456",
-			Quantity:   2,
-			TotalPrice: 1000,
-			Status:     "pending",
+			ID:     id,
+			UserID: "123",
+			Items: []domain.OrderItem{
+				{ProductID: "456", SKU: "SKU-456", UnitPrice: money.New(50000, "USD"), Quantity: 2, LineTotal: money.New(100000, "USD")},
+			},
+			TotalPrice: money.New(100000, "USD"),
+			Status:     domain.OrderStatusPending,
 			CreatedAt:  time.Now(),
 			UpdatedAt:  time.Now(),
+			Version:    3,
 		}
 
-		mockUseCase.On("GetOrder", mock.Anything, id).Return(expectedOrder, nil).Once()
+		mockUseCase.On("GetOrder", mock.Anything, id, false).Return(expectedOrder, nil).Once()
 
 		req := httptest.NewRequest("GET", "/api/v1/orders/"+id.Hex(), nil)
 		rr := httptest.NewRecorder()
@@ -143,9 +244,34 @@ func TestGetOrder(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, expectedOrder.ID, response.ID)
 		assert.Equal(t, expectedOrder.UserID, response.UserID)
+		assert.Equal(t, `"3"`, rr.Header().Get("ETag"))
 		mockUseCase.AssertExpectations(t)
 	})
 
+	t.Run("Include Deleted", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockUseCase.On("GetOrder", mock.Anything, id, true).Return(&domain.Order{ID: id}, nil).Once()
+
+		req := httptest.NewRequest("GET", "/api/v1/orders/"+id.Hex()+"?include_deleted=true", nil)
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Include Deleted", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		req := httptest.NewRequest("GET", "/api/v1/orders/"+id.Hex()+"?include_deleted=maybe", nil)
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, "invalid_query", decodeProblem(t, rr).Code)
+	})
+
 	t.Run("Invalid ID", func(t *testing.T) {
 		req := httptest.NewRequest("GET", "/api/v1/orders/invalid-id", nil)
 		rr := httptest.NewRecorder()
@@ -157,7 +283,7 @@ func TestGetOrder(t *testing.T) {
 
 	t.Run("Not Found", func(t *testing.T) {
 		id := primitive.NewObjectID()
-		mockUseCase.On("GetOrder", mock.Anything, id).Return(nil, nil).Once()
+		mockUseCase.On("GetOrder", mock.Anything, id, false).Return(nil, nil).Once()
 
 		req := httptest.NewRequest("GET", "/api/v1/orders/"+id.Hex(), nil)
 		rr := httptest.NewRecorder()
@@ -172,21 +298,25 @@ func TestGetOrder(t *testing.T) {
 func TestGetOrders(t *testing.T) {
 	mockUseCase := new(MockOrderUseCase)
 	router := mux.NewRouter()
-	NewOrderHandler(router, mockUseCase)
+	NewOrderHandler(router, mockUseCase, nil)
 
 	t.Run("Success", func(t *testing.T) {
 		userID := "123"
-		expectedOrders := []domain.Order{
-			{
-				ID:         primitive.NewObjectID(),
-				UserID:     userID,
-				ProductID:  "456",
-				Quantity:   2,
-				TotalPrice: 1000,
+		expectedPage := &domain.OrderPage{
+			Orders: []domain.Order{
+				{
+					ID:     primitive.NewObjectID(),
+					UserID: userID,
+					Items: []domain.OrderItem{
+						{ProductID: "456", SKU: "SKU-456", UnitPrice: money.New(50000, "USD"), Quantity: 2, LineTotal: money.New(100000, "USD")},
+					},
+					TotalPrice: money.New(100000, "USD"),
+				},
 			},
+			NextCursor: "next-page",
 		}
 
-		mockUseCase.On("GetOrders", mock.Anything, userID).Return(expectedOrders, nil).Once()
+		mockUseCase.On("GetOrders", mock.Anything, domain.OrderFilter{UserID: userID}).Return(expectedPage, nil).Once()
 
 		req := httptest.NewRequest("GET", "/api/v1/orders?user_id="+userID, nil)
 		rr := httptest.NewRecorder()
@@ -195,10 +325,414 @@ func TestGetOrders(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, rr.Code)
 
-		var response []domain.Order
+		var response domain.OrderPage
+		err := json.Unmarshal(rr.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, len(expectedPage.Orders), len(response.Orders))
+		assert.Equal(t, "next-page", response.NextCursor)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Filters", func(t *testing.T) {
+		from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+		minTotal, maxTotal := money.New(10000, "EUR"), money.New(200050, "EUR")
+
+		mockUseCase.On("GetOrders", mock.Anything, domain.OrderFilter{
+			Status:         domain.OrderStatusPaid,
+			ProductID:      "456",
+			CreatedFrom:    &from,
+			CreatedTo:      &to,
+			MinTotal:       &minTotal,
+			MaxTotal:       &maxTotal,
+			Sort:           domain.SortTotalPriceDesc,
+			Limit:          50,
+			After:          "cursor",
+			IncludeDeleted: true,
+		}).Return(&domain.OrderPage{Orders: []domain.Order{}}, nil).Once()
+
+		req := httptest.NewRequest("GET", "/api/v1/orders?status=paid&product_id=456"+
+			"&created_from=2024-03-01T00:00:00Z&created_to=2024-04-01T00:00:00Z"+
+			"&min_total=100&max_total=2000.5&currency=eur&sort=-total_price&limit=50&after=cursor&include_deleted=true", nil)
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Query", func(t *testing.T) {
+		for _, query := range []string{"limit=abc", "limit=0", "status=processing", "created_from=yesterday", "min_total=cheap", "min_total=1.001", "min_total=1&currency=XXX"} {
+			req := httptest.NewRequest("GET", "/api/v1/orders?"+query, nil)
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+		}
+	})
+
+	t.Run("Invalid Cursor", func(t *testing.T) {
+		mockUseCase.On("GetOrders", mock.Anything, domain.OrderFilter{After: "garbage"}).Return(nil, domain.ErrInvalidCursor).Once()
+
+		req := httptest.NewRequest("GET", "/api/v1/orders?after=garbage", nil)
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+		assert.Equal(t, "invalid_cursor", decodeProblem(t, rr).Code)
+		mockUseCase.AssertExpectations(t)
+	})
+}
+
+func TestTransitionOrder(t *testing.T) {
+	mockUseCase := new(MockOrderUseCase)
+	router := mux.NewRouter()
+	NewOrderHandler(router, mockUseCase, nil)
+
+	t.Run("Success", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockUseCase.On("TransitionOrder", mock.Anything, id, domain.OrderStatusConfirmed).
+			Return(&domain.Order{ID: id, Status: domain.OrderStatusConfirmed}, nil).Once()
+
+		req := httptest.NewRequest("POST", "/api/v1/orders/"+id.Hex()+"/transitions", bytes.NewBufferString(`{"status":"confirmed"}`))
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var response domain.Order
+		err := json.Unmarshal(rr.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.OrderStatusConfirmed, response.Status)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Status", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		req := httptest.NewRequest("POST", "/api/v1/orders/"+id.Hex()+"/transitions", bytes.NewBufferString(`{"status":"processing"}`))
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+		assert.Equal(t, []FieldError{{Field: "status", Message: "must be a known order status"}}, decodeProblem(t, rr).Errors)
+	})
+
+	t.Run("Invalid Transition", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockUseCase.On("TransitionOrder", mock.Anything, id, domain.OrderStatusDelivered).
+			Return(nil, &domain.ErrInvalidTransition{From: domain.OrderStatusPending, To: domain.OrderStatusDelivered}).Once()
+
+		req := httptest.NewRequest("POST", "/api/v1/orders/"+id.Hex()+"/transitions", bytes.NewBufferString(`{"status":"delivered"}`))
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		assert.Equal(t, "invalid_transition", decodeProblem(t, rr).Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockUseCase.On("TransitionOrder", mock.Anything, id, domain.OrderStatusConfirmed).
+			Return(nil, domain.ErrOrderNotFound).Once()
+
+		req := httptest.NewRequest("POST", "/api/v1/orders/"+id.Hex()+"/transitions", bytes.NewBufferString(`{"status":"confirmed"}`))
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+}
+
+func TestUpdateOrder(t *testing.T) {
+	mockUseCase := new(MockOrderUseCase)
+	router := mux.NewRouter()
+	NewOrderHandler(router, mockUseCase, nil)
+
+	t.Run("Success", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockUseCase.On("UpdateOrder", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+			return o.ID == id && o.Status == domain.OrderStatusConfirmed
+		})).Return(nil).Once()
+
+		req := httptest.NewRequest("PUT", "/api/v1/orders/"+id.Hex(), bytes.NewBufferString(`{"items":[{"product_id":"456","quantity":1}],"status":"confirmed"}`))
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockUseCase.On("UpdateOrder", mock.Anything, mock.Anything).Return(domain.ErrOrderNotFound).Once()
+
+		req := httptest.NewRequest("PUT", "/api/v1/orders/"+id.Hex(), bytes.NewBufferString(`{"items":[{"product_id":"456","quantity":1}],"status":"confirmed"}`))
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		problem := decodeProblem(t, rr)
+		assert.Equal(t, "order_not_found", problem.Code)
+		assert.Equal(t, "/api/v1/orders/"+id.Hex(), problem.Instance)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Catalog Unavailable", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockUseCase.On("UpdateOrder", mock.Anything, mock.Anything).
+			Return(fmt.Errorf("%w: dial tcp 10.0.0.7:8082: connection refused", domain.ErrCatalogUnavailable)).Once()
+
+		req := httptest.NewRequest("PUT", "/api/v1/orders/"+id.Hex(), bytes.NewBufferString(`{"items":[{"product_id":"456","quantity":1}],"status":"pending"}`))
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+		assert.Equal(t, "catalog_unavailable", decodeProblem(t, rr).Code)
+		assert.NotContains(t, rr.Body.String(), "10.0.0.7")
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("If-Match", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockUseCase.On("UpdateOrder", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+			return o.ID == id && o.Version == 4
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Order).Version = 5
+		}).Return(nil).Once()
+
+		req := httptest.NewRequest("PUT", "/api/v1/orders/"+id.Hex(), bytes.NewBufferString(`{"items":[{"product_id":"456","quantity":1}],"status":"pending"}`))
+		req.Header.Set("If-Match", `"4"`)
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, `"5"`, rr.Header().Get("ETag"))
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Version Mismatch", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockUseCase.On("UpdateOrder", mock.Anything, mock.Anything).Return(domain.ErrVersionMismatch).Once()
+
+		req := httptest.NewRequest("PUT", "/api/v1/orders/"+id.Hex(), bytes.NewBufferString(`{"items":[{"product_id":"456","quantity":1}],"status":"pending"}`))
+		req.Header.Set("If-Match", `"1"`)
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+		assert.Equal(t, "version_mismatch", decodeProblem(t, rr).Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Malformed If-Match", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		for _, header := range []string{`W/"1"`, `1`, `"abc"`, `"0"`} {
+			req := httptest.NewRequest("PUT", "/api/v1/orders/"+id.Hex(), bytes.NewBufferString(`{"items":[{"product_id":"456","quantity":1}],"status":"pending"}`))
+			req.Header.Set("If-Match", header)
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code, header)
+			assert.Equal(t, "invalid_if_match", decodeProblem(t, rr).Code)
+		}
+	})
+
+	t.Run("Missing Fields", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		req := httptest.NewRequest("PUT", "/api/v1/orders/"+id.Hex(), bytes.NewBufferString(`{"status":"confirmed"}`))
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+		assert.Equal(t, []FieldError{{Field: "items", Message: "is required"}}, decodeProblem(t, rr).Errors)
+	})
+}
+
+func TestPatchOrder(t *testing.T) {
+	mockUseCase := new(MockOrderUseCase)
+	router := mux.NewRouter()
+	NewOrderHandler(router, mockUseCase, nil)
+
+	newPatchRequest := func(id primitive.ObjectID, body string) *http.Request {
+		req := httptest.NewRequest("PATCH", "/api/v1/orders/"+id.Hex(), bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		return req
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		status := domain.OrderStatusConfirmed
+		mockUseCase.On("PatchOrder", mock.Anything, id, domain.OrderPatch{Status: &status}).
+			Return(&domain.Order{ID: id, Status: domain.OrderStatusConfirmed}, nil).Once()
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, newPatchRequest(id, `{"status":"confirmed"}`))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var response domain.Order
 		err := json.Unmarshal(rr.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Equal(t, len(expectedOrders), len(response))
+		assert.Equal(t, domain.OrderStatusConfirmed, response.Status)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("If-Match", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockUseCase.On("PatchOrder", mock.Anything, id, mock.MatchedBy(func(p domain.OrderPatch) bool {
+			return p.Version == 2
+		})).Return(&domain.Order{ID: id, Version: 3}, nil).Once()
+
+		req := newPatchRequest(id, `{"status":"confirmed"}`)
+		req.Header.Set("If-Match", `"2"`)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, `"3"`, rr.Header().Get("ETag"))
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Items Only", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockUseCase.On("PatchOrder", mock.Anything, id, mock.MatchedBy(func(p domain.OrderPatch) bool {
+			return p.Status == nil && len(p.Items) == 1 && p.Items[0].ProductID == "456" && p.Items[0].Quantity == 3
+		})).Return(&domain.Order{ID: id}, nil).Once()
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, newPatchRequest(id, `{"items":[{"product_id":"456","quantity":3}]}`))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Null Removes Field", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, newPatchRequest(id, `{"status":null,"items":null}`))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+		assert.Equal(t, []FieldError{
+			{Field: "items", Message: "cannot be removed"},
+			{Field: "status", Message: "cannot be removed"},
+		}, decodeProblem(t, rr).Errors)
+	})
+
+	t.Run("Invalid Status", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, newPatchRequest(id, `{"status":"processing"}`))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+		assert.Equal(t, []FieldError{{Field: "status", Message: "must be a known order status"}}, decodeProblem(t, rr).Errors)
+	})
+
+	t.Run("Unsupported Media Type", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		req := httptest.NewRequest("PATCH", "/api/v1/orders/"+id.Hex(), bytes.NewBufferString(`{"status":"confirmed"}`))
+		req.Header.Set("Content-Type", "text/plain")
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+		assert.Equal(t, "application/merge-patch+json", rr.Header().Get("Accept-Patch"))
+	})
+
+	t.Run("Invalid Transition", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockUseCase.On("PatchOrder", mock.Anything, id, mock.Anything).
+			Return(nil, &domain.ErrInvalidTransition{From: domain.OrderStatusPending, To: domain.OrderStatusDelivered}).Once()
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, newPatchRequest(id, `{"status":"delivered"}`))
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		assert.Equal(t, "invalid_transition", decodeProblem(t, rr).Code)
+		mockUseCase.AssertExpectations(t)
+	})
+}
+
+func TestDeleteOrder(t *testing.T) {
+	mockUseCase := new(MockOrderUseCase)
+	router := mux.NewRouter()
+	NewOrderHandler(router, mockUseCase, nil)
+
+	t.Run("Success", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockUseCase.On("DeleteOrder", mock.Anything, id, int64(0)).Return(nil).Once()
+
+		req := httptest.NewRequest("DELETE", "/api/v1/orders/"+id.Hex(), nil)
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Version Mismatch", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockUseCase.On("DeleteOrder", mock.Anything, id, int64(7)).Return(domain.ErrVersionMismatch).Once()
+
+		req := httptest.NewRequest("DELETE", "/api/v1/orders/"+id.Hex(), nil)
+		req.Header.Set("If-Match", `"7"`)
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+}
+
+func TestRestoreOrder(t *testing.T) {
+	mockUseCase := new(MockOrderUseCase)
+	router := mux.NewRouter()
+	NewOrderHandler(router, mockUseCase, nil)
+
+	t.Run("Success", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockUseCase.On("RestoreOrder", mock.Anything, id, int64(2)).Return(&domain.Order{ID: id, Version: 3}, nil).Once()
+
+		req := httptest.NewRequest("POST", "/api/v1/orders/"+id.Hex()+"/restore", nil)
+		req.Header.Set("If-Match", `"2"`)
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, `"3"`, rr.Header().Get("ETag"))
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Not Deleted", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockUseCase.On("RestoreOrder", mock.Anything, id, int64(0)).Return(nil, domain.ErrOrderNotDeleted).Once()
+
+		req := httptest.NewRequest("POST", "/api/v1/orders/"+id.Hex()+"/restore", nil)
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		assert.Equal(t, "order_not_deleted", decodeProblem(t, rr).Code)
 		mockUseCase.AssertExpectations(t)
 	})
 }
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/yourusername/ecommerce/pkg/money"
+	"order-service/internal/domain"
+)
+
+const defaultFilterCurrency = "USD"
+
+// parseOrderFilter reads the list filters from the query string of
+// GET /api/v1/orders. Range bounds are RFC 3339 timestamps and decimal prices.
+// Price bounds are in the currency given by the currency parameter, USD if
+// it is omitted.
+func parseOrderFilter(query url.Values) (domain.OrderFilter, error) {
+	filter := domain.OrderFilter{
+		UserID:    query.Get("user_id"),
+		Status:    domain.OrderStatus(query.Get("status")),
+		ProductID: query.Get("product_id"),
+		Sort:      domain.OrderSort(query.Get("sort")),
+		After:     query.Get("after"),
+	}
+
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return filter, fmt.Errorf("invalid status %q", filter.Status)
+	}
+
+	if v := query.Get("include_deleted"); v != "" {
+		includeDeleted, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, fmt.Errorf("invalid include_deleted %q", v)
+		}
+		filter.IncludeDeleted = includeDeleted
+	}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return filter, fmt.Errorf("invalid limit %q", v)
+		}
+		filter.Limit = limit
+	}
+
+	var err error
+	if filter.CreatedFrom, err = parseTimeParam(query, "created_from"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedTo, err = parseTimeParam(query, "created_to"); err != nil {
+		return filter, err
+	}
+	currency := query.Get("currency")
+	if currency == "" {
+		currency = defaultFilterCurrency
+	}
+	if filter.MinTotal, err = parseMoneyParam(query, "min_total", currency); err != nil {
+		return filter, err
+	}
+	if filter.MaxTotal, err = parseMoneyParam(query, "max_total", currency); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+func parseTimeParam(query url.Values, name string) (*time.Time, error) {
+	v := query.Get(name)
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: expected RFC 3339 timestamp", name, v)
+	}
+	return &t, nil
+}
+
+func parseMoneyParam(query url.Values, name, currency string) (*money.Money, error) {
+	v := query.Get(name)
+	if v == "" {
+		return nil, nil
+	}
+	m, err := money.Parse(v, currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %v", name, v, err)
+	}
+	return &m, nil
+}
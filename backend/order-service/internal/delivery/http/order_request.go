@@ -0,0 +1,191 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"order-service/internal/domain"
+)
+
+// maxRequestBodyBytes caps JSON request bodies; orders are small, so anything
+// larger is either a mistake or abuse.
+const maxRequestBodyBytes = 1 << 20
+
+type orderItemRequest struct {
+	ProductID string `json:"product_id" validate:"required,max=64"`
+	Quantity  int    `json:"quantity" validate:"required,min=1,max=1000"`
+}
+
+type createOrderRequest struct {
+	UserID string             `json:"user_id" validate:"omitempty,max=64"`
+	Items  []orderItemRequest `json:"items" validate:"required,min=1,max=100,dive"`
+}
+
+// updateOrderRequest replaces an order, so every field is required rather
+// than silently reset to its zero value.
+type updateOrderRequest struct {
+	Items  []orderItemRequest `json:"items" validate:"required,min=1,max=100,dive"`
+	Status domain.OrderStatus `json:"status" validate:"required,order_status"`
+}
+
+// patchOrderRequest is a JSON Merge Patch (RFC 7396) document; absent fields
+// are left unchanged. Explicit nulls are rejected before decoding because
+// neither field can be removed from an order.
+type patchOrderRequest struct {
+	Items  *[]orderItemRequest `json:"items" validate:"omitempty,min=1,max=100,dive"`
+	Status *domain.OrderStatus `json:"status" validate:"omitempty,order_status"`
+}
+
+type transitionRequest struct {
+	Status domain.OrderStatus `json:"status" validate:"required,order_status"`
+}
+
+func (req createOrderRequest) toOrder() domain.Order {
+	return domain.Order{
+		UserID: req.UserID,
+		Items:  toOrderItems(req.Items),
+	}
+}
+
+func (req updateOrderRequest) toOrder() domain.Order {
+	return domain.Order{
+		Items:  toOrderItems(req.Items),
+		Status: req.Status,
+	}
+}
+
+func (req patchOrderRequest) toPatch() domain.OrderPatch {
+	var patch domain.OrderPatch
+	if req.Items != nil {
+		patch.Items = toOrderItems(*req.Items)
+	}
+	patch.Status = req.Status
+	return patch
+}
+
+func toOrderItems(items []orderItemRequest) []domain.OrderItem {
+	result := make([]domain.OrderItem, len(items))
+	for i, item := range items {
+		result[i] = domain.OrderItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		}
+	}
+	return result
+}
+
+// FieldError describes one invalid field in a request body, addressed by its
+// JSON path (for example "items[0].quantity").
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	v.RegisterValidation("order_status", func(fl validator.FieldLevel) bool {
+		return domain.OrderStatus(fl.Field().String()).IsValid()
+	})
+	return v
+}
+
+// decodeRequest reads a size-limited JSON body into dst, rejecting unknown
+// fields, and validates it. On failure it writes the problem response and
+// returns false.
+func decodeRequest(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	body, ok := readBody(w, r)
+	if !ok {
+		return false
+	}
+	return decodeBody(w, r, body, dst)
+}
+
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeProblem(w, r, http.StatusRequestEntityTooLarge, "payload_too_large",
+				fmt.Sprintf("request body must not exceed %d bytes", maxRequestBodyBytes))
+			return nil, false
+		}
+		writeProblem(w, r, http.StatusBadRequest, "malformed_request", err.Error())
+		return nil, false
+	}
+	return body, true
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, body []byte, dst interface{}) bool {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(dst); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "malformed_request", err.Error())
+		return false
+	}
+	if err := decoder.Decode(&struct{}{}); err != io.EOF {
+		writeProblem(w, r, http.StatusBadRequest, "malformed_request", "request body must contain a single JSON object")
+		return false
+	}
+
+	if err := validate.Struct(dst); err != nil {
+		var validationErrs validator.ValidationErrors
+		if !errors.As(err, &validationErrs) {
+			writeError(w, r, err)
+			return false
+		}
+		writeValidationProblem(w, r, toFieldErrors(validationErrs))
+		return false
+	}
+	return true
+}
+
+func toFieldErrors(errs validator.ValidationErrors) []FieldError {
+	fields := make([]FieldError, 0, len(errs))
+	for _, fe := range errs {
+		// Namespace is "<struct>.<json path>"; drop the Go type name.
+		_, path, _ := strings.Cut(fe.Namespace(), ".")
+		fields = append(fields, FieldError{Field: path, Message: fieldErrorMessage(fe)})
+	}
+	return fields
+}
+
+func fieldErrorMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		if fe.Kind() == reflect.Slice {
+			return fmt.Sprintf("must contain at least %s item(s)", fe.Param())
+		}
+		return "must be at least " + fe.Param()
+	case "max":
+		if fe.Kind() == reflect.Slice {
+			return fmt.Sprintf("must contain at most %s item(s)", fe.Param())
+		}
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at most %s characters", fe.Param())
+		}
+		return "must be at most " + fe.Param()
+	case "order_status":
+		return "must be a known order status"
+	default:
+		return "is invalid"
+	}
+}
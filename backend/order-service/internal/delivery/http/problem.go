@@ -0,0 +1,98 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"order-service/internal/domain"
+)
+
+const problemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details body. Code is a stable,
+// machine-readable identifier that clients can switch on.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+	// Errors lists per-field failures for validation problems.
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, code, detail string) {
+	sendProblem(w, newProblem(r, status, code, detail))
+}
+
+func writeValidationProblem(w http.ResponseWriter, r *http.Request, fields []FieldError) {
+	problem := newProblem(r, http.StatusUnprocessableEntity, "validation_failed", "the request body has invalid fields")
+	problem.Errors = fields
+	sendProblem(w, problem)
+}
+
+func newProblem(r *http.Request, status int, code, detail string) Problem {
+	return Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+		Code:     code,
+	}
+}
+
+func sendProblem(w http.ResponseWriter, problem Problem) {
+	status := problem.Status
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(problem)
+}
+
+// writeError maps err onto a problem response. Domain errors keep their code
+// and message; anything else is logged and reported as a bare 500 so driver
+// and network details never reach the client.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var domainErr *domain.Error
+	var transitionErr *domain.ErrInvalidTransition
+
+	switch {
+	case errors.As(err, &transitionErr):
+		writeProblem(w, r, http.StatusConflict, "invalid_transition", transitionErr.Error())
+	case errors.As(err, &domainErr):
+		status := statusForKind(domainErr.Kind)
+		detail := err.Error()
+		if status >= http.StatusInternalServerError {
+			slog.ErrorContext(r.Context(), "request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+			detail = domainErr.Message
+		}
+		writeProblem(w, r, status, domainErr.Code, detail)
+	default:
+		slog.ErrorContext(r.Context(), "request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+		writeProblem(w, r, http.StatusInternalServerError, "internal_error", "")
+	}
+}
+
+func statusForKind(kind error) int {
+	switch kind {
+	case domain.ErrNotFound:
+		return http.StatusNotFound
+	case domain.ErrValidation:
+		return http.StatusUnprocessableEntity
+	case domain.ErrConflict:
+		return http.StatusConflict
+	case domain.ErrUnauthorized:
+		return http.StatusUnauthorized
+	case domain.ErrUpstreamUnavailable:
+		return http.StatusServiceUnavailable
+	case domain.ErrPreconditionFailed:
+		return http.StatusPreconditionFailed
+	case domain.ErrPaymentFailed:
+		return http.StatusPaymentRequired
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,58 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// Instrument wraps router with request IDs, access logging and metrics.
+// They wrap the whole router instead of going through router.Use, which
+// only runs for matched routes, so requests answered with 404 or 405 are
+// logged and counted too.
+func Instrument(router *mux.Router, accessLog bool) http.Handler {
+	router.Use(recordRoute)
+	return MetricsMiddleware(LoggingMiddleware(accessLog)(router))
+}
+
+type routeKey struct{}
+
+// matchedRoute carries the template of the route the router matched out to
+// middleware wrapping the router. mux sets the route only on the request it
+// passes down, which such middleware never sees.
+type matchedRoute struct {
+	template string
+}
+
+// withMatchedRoute returns r with a matchedRoute in its context, reusing one
+// an outer middleware already added.
+func withMatchedRoute(r *http.Request) (*http.Request, *matchedRoute) {
+	if matched, ok := r.Context().Value(routeKey{}).(*matchedRoute); ok {
+		return r, matched
+	}
+	matched := &matchedRoute{}
+	return r.WithContext(context.WithValue(r.Context(), routeKey{}, matched)), matched
+}
+
+// recordRoute fills in the matchedRoute once the router has matched a route.
+func recordRoute(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if matched, ok := r.Context().Value(routeKey{}).(*matchedRoute); ok {
+			if current := mux.CurrentRoute(r); current != nil {
+				matched.template, _ = current.GetPathTemplate()
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// routeTemplate returns the template of the route r matched, or "" if none
+// did. Inside the router mux knows the route; around it, recordRoute does.
+func routeTemplate(r *http.Request, matched *matchedRoute) string {
+	if current := mux.CurrentRoute(r); current != nil {
+		template, _ := current.GetPathTemplate()
+		return template
+	}
+	return matched.template
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/ecommerce/pkg/logging"
+	"github.com/yourusername/ecommerce/pkg/metrics"
+)
+
+func TestInstrument(t *testing.T) {
+	var buf bytes.Buffer
+	handler, err := logging.NewHandler(&buf, "info", logging.FormatJSON)
+	require.NoError(t, err)
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(handler))
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/carts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}).Methods(http.MethodGet)
+	instrumented := Instrument(router, true)
+
+	accessLine := func(t *testing.T) map[string]interface{} {
+		var line map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+		return line
+	}
+
+	t.Run("Matched Route", func(t *testing.T) {
+		buf.Reset()
+		rr := httptest.NewRecorder()
+
+		instrumented.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/carts/42", nil))
+
+		assert.Equal(t, http.StatusAccepted, rr.Code)
+		assert.Equal(t, "/api/v1/carts/{id}", accessLine(t)["route"])
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		buf.Reset()
+		rr := httptest.NewRecorder()
+
+		instrumented.ServeHTTP(rr, httptest.NewRequest("GET", "/wp-login.php", nil))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		assert.NotEmpty(t, rr.Header().Get(logging.HeaderRequestID))
+		line := accessLine(t)
+		assert.Equal(t, "", line["route"])
+		assert.Equal(t, float64(http.StatusNotFound), line["status"])
+	})
+
+	t.Run("Method Not Allowed", func(t *testing.T) {
+		instrumented.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("DELETE", "/api/v1/carts/42", nil))
+	})
+
+	rr := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Contains(t, rr.Body.String(), `http_requests_total{method="GET",route="/api/v1/carts/{id}",status="202"} 1`)
+	assert.Contains(t, rr.Body.String(), `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, rr.Body.String(), `http_requests_total{method="DELETE",route="unmatched",status="405"} 1`)
+}
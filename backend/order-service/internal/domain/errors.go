@@ -0,0 +1,35 @@
+package domain
+
+import "errors"
+
+// Error kinds. Every error the domain returns to callers wraps exactly one of
+// these, so the delivery layer can map a whole class of failures at once.
+var (
+	ErrNotFound            = errors.New("not found")
+	ErrValidation          = errors.New("validation failed")
+	ErrConflict            = errors.New("conflict")
+	ErrUnauthorized        = errors.New("unauthorized")
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+	ErrPreconditionFailed  = errors.New("precondition failed")
+	ErrPaymentFailed       = errors.New("payment failed")
+)
+
+// Error is a domain error with a stable, machine-readable code. Codes are part
+// of the public API and must not change once released.
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+}
+
+func NewError(kind error, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/ecommerce/pkg/money"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type EventType string
+
+const (
+	EventOrderCreated       EventType = "order.created"
+	EventOrderStatusChanged EventType = "order.status_changed"
+	EventOrderCancelled     EventType = "order.cancelled"
+	EventOrderDeleted       EventType = "order.deleted"
+	EventOrderRestored      EventType = "order.restored"
+)
+
+// OrderEvent is a domain event about one order. It carries a snapshot of the
+// fields consumers usually need so they do not have to call back for them.
+type OrderEvent struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id"`
+	Type           EventType          `json:"type" bson:"type"`
+	OrderID        primitive.ObjectID `json:"order_id" bson:"order_id"`
+	UserID         string             `json:"user_id" bson:"user_id"`
+	Status         OrderStatus        `json:"status" bson:"status"`
+	PreviousStatus OrderStatus        `json:"previous_status,omitempty" bson:"previous_status,omitempty"`
+	TotalPrice     money.Money        `json:"total_price" bson:"total_price"`
+	OccurredAt     time.Time          `json:"occurred_at" bson:"occurred_at"`
+}
+
+func NewOrderEvent(eventType EventType, order *Order, previous OrderStatus) OrderEvent {
+	return OrderEvent{
+		ID:             primitive.NewObjectID(),
+		Type:           eventType,
+		OrderID:        order.ID,
+		UserID:         order.UserID,
+		Status:         order.Status,
+		PreviousStatus: previous,
+		TotalPrice:     order.TotalPrice,
+		OccurredAt:     time.Now(),
+	}
+}
+
+// StatusChangeEvents returns the events for order having moved from previous
+// to its current status: always OrderStatusChanged, plus OrderCancelled when
+// the order was cancelled.
+func StatusChangeEvents(order *Order, previous OrderStatus) []OrderEvent {
+	if order.Status == previous {
+		return nil
+	}
+	events := []OrderEvent{NewOrderEvent(EventOrderStatusChanged, order, previous)}
+	if order.Status == OrderStatusCancelled {
+		events = append(events, NewOrderEvent(EventOrderCancelled, order, previous))
+	}
+	return events
+}
+
+// OutboxRepository stores events next to the order writes that produced them
+// until the relay has published them.
+type OutboxRepository interface {
+	Add(ctx context.Context, events ...OrderEvent) error
+	// Pending returns up to limit unpublished events, oldest first.
+	Pending(ctx context.Context, limit int) ([]OrderEvent, error)
+	MarkPublished(ctx context.Context, id primitive.ObjectID) error
+}
+
+type EventPublisher interface {
+	Publish(ctx context.Context, event OrderEvent) error
+}
+
+// TxManager runs fn in a transaction. Repositories called with the ctx passed
+// to fn take part in it.
+type TxManager interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+var (
+	ErrIdempotencyKeyReused  = NewError(ErrValidation, "idempotency_key_reused", "idempotency key was already used with a different request body")
+	ErrIdempotencyInProgress = NewError(ErrConflict, "idempotency_request_in_progress", "a request with this idempotency key is still being processed")
+)
+
+// IdempotencyRecord remembers the outcome of a request sent with an
+// Idempotency-Key so that retries get the original response back. Keys are
+// scoped to the user that sent them.
+type IdempotencyRecord struct {
+	Key         string            `bson:"key"`
+	UserID      string            `bson:"user_id"`
+	RequestHash string            `bson:"request_hash"`
+	Completed   bool              `bson:"completed"`
+	StatusCode  int               `bson:"status_code,omitempty"`
+	Header      map[string]string `bson:"header,omitempty"`
+	Body        []byte            `bson:"body,omitempty"`
+	CreatedAt   time.Time         `bson:"created_at"`
+}
+
+type IdempotencyStore interface {
+	// Reserve claims record.Key for a new request. When the key is already
+	// claimed it returns the existing record and leaves the store unchanged.
+	Reserve(ctx context.Context, record *IdempotencyRecord) (*IdempotencyRecord, error)
+	// Complete stores the response for a reserved key.
+	Complete(ctx context.Context, record *IdempotencyRecord) error
+	// Release drops a reservation so the request can be retried from scratch.
+	Release(ctx context.Context, userID, key string) error
+}
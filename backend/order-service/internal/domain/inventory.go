@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInsufficientStock    = NewError(ErrConflict, "insufficient_stock", "not enough stock to fulfil the order")
+	ErrReservationClosed    = NewError(ErrConflict, "reservation_closed", "the stock reservation for this order has expired or was already settled")
+	ErrInventoryUnavailable = NewError(ErrUpstreamUnavailable, "inventory_unavailable", "inventory service is unavailable")
+)
+
+// Inventory holds stock for orders. Reservations are keyed by order ID and
+// owned by the product service; they expire on their own if an order is
+// never paid or cancelled.
+type Inventory interface {
+	// Reserve holds stock for items, or for none of them. Calling it again
+	// for the same order adjusts the reservation to the new items.
+	Reserve(ctx context.Context, orderID primitive.ObjectID, items []OrderItem) error
+	// Commit turns the reservation into a sale.
+	Commit(ctx context.Context, orderID primitive.ObjectID) error
+	// Reopen undoes Commit: the stock is reserved for the order again.
+	Reopen(ctx context.Context, orderID primitive.ObjectID) error
+	// Release gives the reserved stock back.
+	Release(ctx context.Context, orderID primitive.ObjectID) error
+}
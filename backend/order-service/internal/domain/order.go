@@ -4,32 +4,161 @@ import (
 	"context"
 	"time"
 
+	"github.com/yourusername/ecommerce/pkg/money"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+var (
+	ErrOrderNotFound     = NewError(ErrNotFound, "order_not_found", "order not found")
+	ErrInvalidOrderItems = NewError(ErrValidation, "invalid_order_items", "order must contain at least one item with a product ID and a positive quantity")
+	ErrVersionMismatch   = NewError(ErrPreconditionFailed, "version_mismatch", "order has been modified since it was read")
+	ErrOrderNotDeleted   = NewError(ErrConflict, "order_not_deleted", "order is not deleted")
+	ErrMixedCurrencies   = NewError(ErrValidation, "mixed_currencies", "all items of an order must be priced in the same currency")
+	ErrOrderItemsLocked  = NewError(ErrConflict, "order_items_locked", "items can only change while the order is pending")
+)
+
 type Order struct {
-	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	UserID      string            `json:"user_id" bson:"user_id"`
-	ProductID   string            `json:"product_id" bson:"product_id"`
-	Quantity    int               `json:"quantity" bson:"quantity"`
-	TotalPrice  float64           `json:"total_price" bson:"total_price"`
-	Status      string            `json:"status" bson:"status"`
-	CreatedAt   time.Time         `json:"created_at" bson:"created_at"`
-	UpdatedAt   time.Time         `json:"updated_at" bson:"updated_at"`
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID     string             `json:"user_id" bson:"user_id"`
+	Items      []OrderItem        `json:"items" bson:"items"`
+	TotalPrice money.Money        `json:"total_price" bson:"total_price"`
+	Status     OrderStatus        `json:"status" bson:"status"`
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at" bson:"updated_at"`
+	// Version is incremented on every write. Updates are conditional on the
+	// version the writer last read, which makes concurrent writers fail
+	// instead of silently overwriting each other.
+	Version int64 `json:"version" bson:"version"`
+	// DeletedAt and DeletedBy mark a soft-deleted order. Orders are kept for
+	// accounting and hidden from reads unless explicitly requested.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+	DeletedBy string     `json:"deleted_by,omitempty" bson:"deleted_by,omitempty"`
+}
+
+// UnmarshalBSON reads the form Order is stored in, and also orders stored
+// before items existed, with one top-level product_id and quantity, which
+// become a single item priced at an even share of the total.
+func (o *Order) UnmarshalBSON(data []byte) error {
+	// Stored has Order's fields but not this method, so decoding into it does
+	// not recurse.
+	type Stored Order
+	var doc struct {
+		Stored    `bson:",inline"`
+		ProductID string `bson:"product_id"`
+		Quantity  int    `bson:"quantity"`
+	}
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	*o = Order(doc.Stored)
+	if len(o.Items) == 0 && doc.ProductID != "" && doc.Quantity > 0 {
+		o.Items = []OrderItem{LegacyItem(doc.ProductID, doc.Quantity, o.TotalPrice)}
+	}
+	return nil
+}
+
+// LegacyItem is the item of an order stored before items existed, which only
+// recorded the product, the quantity and the total.
+func LegacyItem(productID string, quantity int, total money.Money) OrderItem {
+	return OrderItem{
+		ProductID: productID,
+		Quantity:  quantity,
+		UnitPrice: money.New(total.Amount()/int64(quantity), total.Currency()),
+		LineTotal: total,
+	}
+}
+
+func (o *Order) IsDeleted() bool {
+	return o.DeletedAt != nil
+}
+
+// ItemsLocked reports whether the order's items can no longer change. Past
+// pending its payment has been authorized for the current total, which a
+// change of items would no longer match.
+func (o *Order) ItemsLocked() bool {
+	return o.Status != OrderStatusPending
+}
+
+type OrderItem struct {
+	ProductID string      `json:"product_id" bson:"product_id"`
+	SKU       string      `json:"sku" bson:"sku"`
+	UnitPrice money.Money `json:"unit_price" bson:"unit_price"`
+	Quantity  int         `json:"quantity" bson:"quantity"`
+	LineTotal money.Money `json:"line_total" bson:"line_total"`
+}
+
+// CalculateTotals fills in each item's line total and the order total from
+// unit prices and quantities, so stored totals never come from the client.
+// All items must share one currency.
+func (o *Order) CalculateTotals() error {
+	if len(o.Items) == 0 {
+		return ErrInvalidOrderItems
+	}
+
+	var total money.Money
+	for i := range o.Items {
+		item := &o.Items[i]
+		if item.ProductID == "" || item.Quantity <= 0 {
+			return ErrInvalidOrderItems
+		}
+		item.LineTotal = item.UnitPrice.Mul(int64(item.Quantity))
+		sum, err := total.Add(item.LineTotal)
+		if err != nil {
+			return ErrMixedCurrencies
+		}
+		total = sum
+	}
+	o.TotalPrice = total
+	return nil
+}
+
+// CheckVersion reports ErrVersionMismatch when expected is set and differs
+// from the order's current version. Zero means the caller did not ask for a
+// conditional write.
+func (o *Order) CheckVersion(expected int64) error {
+	if expected != 0 && expected != o.Version {
+		return ErrVersionMismatch
+	}
+	return nil
+}
+
+// OrderPatch describes a partial update. Nil fields are left untouched.
+// Version is the version the patch must be applied to.
+type OrderPatch struct {
+	Items      []OrderItem
+	TotalPrice *money.Money
+	Status     *OrderStatus
+	UpdatedAt  time.Time
+	Version    int64
+}
+
+func (p OrderPatch) IsEmpty() bool {
+	return p.Items == nil && p.TotalPrice == nil && p.Status == nil
 }
 
 type OrderRepository interface {
 	Create(ctx context.Context, order *Order) error
 	GetByID(ctx context.Context, id primitive.ObjectID) (*Order, error)
-	GetAll(ctx context.Context, userID string) ([]Order, error)
+	GetAll(ctx context.Context, filter OrderFilter) (*OrderPage, error)
+	// Update writes order if its stored version still equals order.Version
+	// and bumps the stored version. order is not changed: callers increment
+	// order.Version once the write, and any transaction around it, commits.
 	Update(ctx context.Context, order *Order) error
-	Delete(ctx context.Context, id primitive.ObjectID) error
+	Patch(ctx context.Context, id primitive.ObjectID, patch OrderPatch) (*Order, error)
+	// Delete and Restore set or clear the soft-delete fields of order, and
+	// write its status, under the same version check as Update.
+	Delete(ctx context.Context, order *Order) error
+	Restore(ctx context.Context, order *Order) error
 }
 
 type OrderUseCase interface {
 	CreateOrder(ctx context.Context, order *Order) error
-	GetOrder(ctx context.Context, id primitive.ObjectID) (*Order, error)
-	GetOrders(ctx context.Context, userID string) ([]Order, error)
+	GetOrder(ctx context.Context, id primitive.ObjectID, includeDeleted bool) (*Order, error)
+	GetOrders(ctx context.Context, filter OrderFilter) (*OrderPage, error)
 	UpdateOrder(ctx context.Context, order *Order) error
-	DeleteOrder(ctx context.Context, id primitive.ObjectID) error
+	PatchOrder(ctx context.Context, id primitive.ObjectID, patch OrderPatch) (*Order, error)
+	TransitionOrder(ctx context.Context, id primitive.ObjectID, status OrderStatus) (*Order, error)
+	DeleteOrder(ctx context.Context, id primitive.ObjectID, version int64) error
+	RestoreOrder(ctx context.Context, id primitive.ObjectID, version int64) (*Order, error)
 }
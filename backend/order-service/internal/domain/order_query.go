@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/yourusername/ecommerce/pkg/money"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+var (
+	ErrInvalidCursor = NewError(ErrValidation, "invalid_cursor", "invalid pagination cursor")
+	ErrInvalidSort   = NewError(ErrValidation, "invalid_sort", "invalid sort order")
+)
+
+// OrderSort names the field orders are listed by; a leading "-" means descending.
+type OrderSort string
+
+const (
+	SortCreatedAtAsc   OrderSort = "created_at"
+	SortCreatedAtDesc  OrderSort = "-created_at"
+	SortTotalPriceAsc  OrderSort = "total_price"
+	SortTotalPriceDesc OrderSort = "-total_price"
+)
+
+func (s OrderSort) IsValid() bool {
+	switch s {
+	case SortCreatedAtAsc, SortCreatedAtDesc, SortTotalPriceAsc, SortTotalPriceDesc:
+		return true
+	}
+	return false
+}
+
+func (s OrderSort) Field() string {
+	if len(s) > 0 && s[0] == '-' {
+		return string(s[1:])
+	}
+	return string(s)
+}
+
+func (s OrderSort) Descending() bool {
+	return len(s) > 0 && s[0] == '-'
+}
+
+type OrderFilter struct {
+	UserID      string
+	Status      OrderStatus
+	ProductID   string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	// MinTotal and MaxTotal bound the order total. Both are in the same
+	// currency, and orders in other currencies never match.
+	MinTotal *money.Money
+	MaxTotal *money.Money
+	Sort     OrderSort
+	Limit    int
+	// After is the opaque cursor returned as NextCursor by the previous page.
+	After string
+	// IncludeDeleted lists soft-deleted orders too. Only honoured for admins.
+	IncludeDeleted bool
+}
+
+type OrderPage struct {
+	Orders     []Order `json:"data"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,76 @@
+package domain
+
+import "fmt"
+
+type OrderStatus string
+
+const (
+	OrderStatusPending   OrderStatus = "pending"
+	OrderStatusConfirmed OrderStatus = "confirmed"
+	OrderStatusPaid      OrderStatus = "paid"
+	OrderStatusShipped   OrderStatus = "shipped"
+	OrderStatusDelivered OrderStatus = "delivered"
+	OrderStatusCancelled OrderStatus = "cancelled"
+	OrderStatusRefunded  OrderStatus = "refunded"
+	// OrderStatusFailed marks an order whose checkout could not complete and
+	// was rolled back.
+	OrderStatusFailed OrderStatus = "failed"
+)
+
+// orderTransitions lists, for each status, the statuses an order may move to next.
+// Statuses without an entry are terminal.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending:   {OrderStatusConfirmed, OrderStatusCancelled, OrderStatusFailed},
+	OrderStatusConfirmed: {OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusPaid:      {OrderStatusShipped, OrderStatusRefunded},
+	OrderStatusShipped:   {OrderStatusDelivered},
+	OrderStatusDelivered: {OrderStatusRefunded},
+}
+
+// OrderStatuses lists every valid status.
+func OrderStatuses() []OrderStatus {
+	return []OrderStatus{
+		OrderStatusPending, OrderStatusConfirmed, OrderStatusPaid, OrderStatusShipped,
+		OrderStatusDelivered, OrderStatusCancelled, OrderStatusRefunded, OrderStatusFailed,
+	}
+}
+
+func (s OrderStatus) IsValid() bool {
+	for _, valid := range OrderStatuses() {
+		if s == valid {
+			return true
+		}
+	}
+	return false
+}
+
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrInvalidTransition is returned when an order is asked to move to a status
+// that is unknown or not reachable from its current one.
+type ErrInvalidTransition struct {
+	From OrderStatus
+	To   OrderStatus
+}
+
+func (e *ErrInvalidTransition) Error() string {
+	return fmt.Sprintf("invalid order status transition from %q to %q", e.From, e.To)
+}
+
+func (e *ErrInvalidTransition) Unwrap() error {
+	return ErrConflict
+}
+
+func (s OrderStatus) TransitionTo(next OrderStatus) error {
+	if !next.IsValid() || !s.CanTransitionTo(next) {
+		return &ErrInvalidTransition{From: s, To: next}
+	}
+	return nil
+}
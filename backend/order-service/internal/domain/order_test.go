@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/ecommerce/pkg/money"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestOrderUnmarshalBSON(t *testing.T) {
+	t.Run("Round Trip", func(t *testing.T) {
+		order := Order{
+			ID:     primitive.NewObjectID(),
+			UserID: "123",
+			Items: []OrderItem{{
+				ProductID: "456", SKU: "SKU-456", Quantity: 2,
+				UnitPrice: money.New(1000, "USD"), LineTotal: money.New(2000, "USD"),
+			}},
+			TotalPrice: money.New(2000, "USD"),
+			Status:     OrderStatusPaid,
+			CreatedAt:  time.Now().UTC().Truncate(time.Millisecond),
+			UpdatedAt:  time.Now().UTC().Truncate(time.Millisecond),
+			Version:    3,
+		}
+		raw, err := bson.Marshal(order)
+		require.NoError(t, err)
+
+		var decoded Order
+		require.NoError(t, bson.Unmarshal(raw, &decoded))
+
+		assert.Equal(t, order, decoded)
+	})
+
+	t.Run("Single Product Order", func(t *testing.T) {
+		// The shape orders were stored in before items and Money.
+		raw, err := bson.Marshal(bson.M{
+			"_id":         primitive.NewObjectID(),
+			"user_id":     "123",
+			"product_id":  "456",
+			"quantity":    3,
+			"total_price": 29.97,
+			"status":      "pending",
+		})
+		require.NoError(t, err)
+
+		var order Order
+		require.NoError(t, bson.Unmarshal(raw, &order))
+
+		assert.Equal(t, []OrderItem{{
+			ProductID: "456", Quantity: 3,
+			UnitPrice: money.New(999, money.LegacyCurrency), LineTotal: money.New(2997, money.LegacyCurrency),
+		}}, order.Items)
+		assert.Equal(t, money.New(2997, money.LegacyCurrency), order.TotalPrice)
+		assert.NoError(t, order.CalculateTotals())
+	})
+}
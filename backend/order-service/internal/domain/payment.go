@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/ecommerce/pkg/money"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrPaymentDeclined    = NewError(ErrPaymentFailed, "payment_declined", "payment was declined")
+	ErrPaymentUnavailable = NewError(ErrUpstreamUnavailable, "payment_unavailable", "payment provider is unavailable")
+	ErrPaymentNotFound    = NewError(ErrConflict, "payment_not_found", "order has no payment")
+	ErrPaymentClosed      = NewError(ErrConflict, "payment_closed", "payment is not in a state that allows this operation")
+	ErrPaymentMismatch    = NewError(ErrConflict, "payment_amount_mismatch", "order total differs from the authorized amount")
+)
+
+type PaymentStatus string
+
+const (
+	PaymentAuthorized PaymentStatus = "authorized"
+	PaymentCaptured   PaymentStatus = "captured"
+	PaymentVoided     PaymentStatus = "voided"
+	PaymentRefunded   PaymentStatus = "refunded"
+)
+
+// Payment records the money side of one order. Reference is the gateway's
+// ID for the authorization and is what later calls to the gateway use.
+type Payment struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	OrderID   primitive.ObjectID `json:"order_id" bson:"order_id"`
+	Reference string             `json:"reference" bson:"reference"`
+	Amount    money.Money        `json:"amount" bson:"amount"`
+	Status    PaymentStatus      `json:"status" bson:"status"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+type PaymentRepository interface {
+	Create(ctx context.Context, payment *Payment) error
+	Update(ctx context.Context, payment *Payment) error
+	GetByOrderID(ctx context.Context, orderID primitive.ObjectID) (*Payment, error)
+}
+
+// PaymentRequest is what a gateway needs to authorize an order. OrderID is
+// passed on as the idempotency key, so authorizing twice holds funds once.
+type PaymentRequest struct {
+	OrderID primitive.ObjectID
+	Amount  money.Money
+}
+
+// PaymentGateway is a payment provider. Declines are reported as
+// ErrPaymentDeclined and provider outages as ErrPaymentUnavailable.
+type PaymentGateway interface {
+	// Authorize holds funds and returns the provider's reference for them.
+	Authorize(ctx context.Context, req PaymentRequest) (string, error)
+	// Capture collects amount, at most the authorized amount.
+	Capture(ctx context.Context, reference string, amount money.Money) error
+	// Void cancels an authorization that has not been captured.
+	Void(ctx context.Context, reference string) error
+	// Refund returns amount of a captured payment.
+	Refund(ctx context.Context, reference string, amount money.Money) error
+}
+
+// Payments takes payment for orders and keeps their payment records. Every
+// call is idempotent per order, since a resumed checkout may repeat it.
+type Payments interface {
+	Authorize(ctx context.Context, order *Order) (*Payment, error)
+	// Capture collects the authorized amount, which must equal amount, the
+	// order total at the time; otherwise it returns ErrPaymentMismatch.
+	Capture(ctx context.Context, orderID primitive.ObjectID, amount money.Money) (*Payment, error)
+	// Void cancels the order's authorization. An order without a payment
+	// has nothing to void.
+	Void(ctx context.Context, orderID primitive.ObjectID) error
+	Refund(ctx context.Context, orderID primitive.ObjectID) (*Payment, error)
+}
@@ -0,0 +1,46 @@
+package domain
+
+import "context"
+
+const (
+	RoleAdmin = "admin"
+	// RoleService marks tokens that services sign for each other, such as
+	// the ones this service sends to the product service.
+	RoleService = "service"
+)
+
+var ErrAuthenticationRequired = NewError(ErrUnauthorized, "authentication_required", "authentication required")
+
+// Principal is the authenticated caller, taken from a verified bearer token.
+type Principal struct {
+	Subject string
+	Roles   []string
+}
+
+func (p *Principal) IsAdmin() bool {
+	for _, role := range p.Roles {
+		if role == RoleAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAccess reports whether the principal may see or modify an order owned by userID.
+func (p *Principal) CanAccess(userID string) bool {
+	return p.IsAdmin() || p.Subject == userID
+}
+
+type principalKey struct{}
+
+func ContextWithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+func PrincipalFromContext(ctx context.Context) (*Principal, error) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	if !ok || principal == nil || principal.Subject == "" {
+		return nil, ErrAuthenticationRequired
+	}
+	return principal, nil
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/yourusername/ecommerce/pkg/money"
+)
+
+var (
+	ErrProductNotFound    = NewError(ErrValidation, "product_not_found", "product not found")
+	ErrProductUnavailable = NewError(ErrValidation, "product_unavailable", "product is not available for sale")
+	ErrCatalogUnavailable = NewError(ErrUpstreamUnavailable, "catalog_unavailable", "product catalog is unavailable")
+)
+
+// CatalogProduct is the view of a product that the order service needs at
+// order time. It is owned by the product service and only read here.
+type CatalogProduct struct {
+	ID     string      `json:"id"`
+	SKU    string      `json:"sku"`
+	Name   string      `json:"name"`
+	Price  money.Money `json:"price"`
+	Active bool        `json:"active"`
+}
+
+type ProductCatalog interface {
+	GetProduct(ctx context.Context, id string) (*CatalogProduct, error)
+}
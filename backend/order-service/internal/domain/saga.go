@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrSagaLeaseLost is returned when saving a saga whose lease has run out or
+// been taken by another runner. The runner must stop driving it.
+var ErrSagaLeaseLost = NewError(ErrConflict, "saga_lease_lost", "the checkout is being completed by another runner")
+
+// SagaStep names one step of the checkout saga, in the order they run.
+type SagaStep string
+
+const (
+	SagaStepCreateOrder      SagaStep = "create_order"
+	SagaStepReserveStock     SagaStep = "reserve_stock"
+	SagaStepAuthorizePayment SagaStep = "authorize_payment"
+	SagaStepConfirmOrder     SagaStep = "confirm_order"
+)
+
+type SagaStatus string
+
+const (
+	// SagaRunning sagas are still moving forward.
+	SagaRunning SagaStatus = "running"
+	// SagaCompensating sagas hit a failing step and are undoing the steps
+	// they completed.
+	SagaCompensating SagaStatus = "compensating"
+	SagaCompleted    SagaStatus = "completed"
+	// SagaFailed sagas have been fully compensated.
+	SagaFailed SagaStatus = "failed"
+)
+
+// CheckoutSaga is the persisted state of one order's checkout. It is saved
+// after every step so a checkout interrupted by a restart can be resumed.
+type CheckoutSaga struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	OrderID   primitive.ObjectID `json:"order_id" bson:"order_id"`
+	Status    SagaStatus         `json:"status" bson:"status"`
+	Completed []SagaStep         `json:"completed" bson:"completed"`
+	// Compensated lists the completed steps that have since been undone.
+	Compensated []SagaStep         `json:"compensated,omitempty" bson:"compensated,omitempty"`
+	PaymentID   primitive.ObjectID `json:"payment_id,omitempty" bson:"payment_id,omitempty"`
+	Failure     string             `json:"failure,omitempty" bson:"failure,omitempty"`
+	// Owner is the runner driving the saga, until LeaseExpiresAt. Nobody
+	// else may pick the saga up before then.
+	Owner          string    `json:"owner,omitempty" bson:"owner,omitempty"`
+	LeaseExpiresAt time.Time `json:"lease_expires_at,omitempty" bson:"lease_expires_at,omitempty"`
+	CreatedAt      time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+func (s *CheckoutSaga) HasCompleted(step SagaStep) bool {
+	return containsStep(s.Completed, step)
+}
+
+func (s *CheckoutSaga) HasCompensated(step SagaStep) bool {
+	return containsStep(s.Compensated, step)
+}
+
+func containsStep(steps []SagaStep, step SagaStep) bool {
+	for _, s := range steps {
+		if s == step {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinished reports whether the saga has reached a final status.
+func (s *CheckoutSaga) IsFinished() bool {
+	return s.Status == SagaCompleted || s.Status == SagaFailed
+}
+
+type SagaRepository interface {
+	Create(ctx context.Context, saga *CheckoutSaga) error
+	// Update saves the saga as long as saga.Owner still holds an unexpired
+	// lease on it, and returns ErrSagaLeaseLost otherwise.
+	Update(ctx context.Context, saga *CheckoutSaga) error
+	GetByOrderID(ctx context.Context, orderID primitive.ObjectID) (*CheckoutSaga, error)
+	// ClaimStalled atomically takes the unfinished saga least recently
+	// updated before the given time whose lease has run out, giving it to
+	// owner until leaseUntil. It returns nil, nil when there is none.
+	ClaimStalled(ctx context.Context, before time.Time, owner string, leaseUntil time.Time) (*CheckoutSaga, error)
+}
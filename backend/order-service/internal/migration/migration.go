@@ -0,0 +1,154 @@
+// Package migration applies versioned changes to the database (indexes,
+// validators, document rewrites) exactly once per database and records them
+// in the schema_migrations collection.
+package migration
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CollectionName is where applied migrations are recorded.
+const CollectionName = "schema_migrations"
+
+// Migration is one versioned change. Up must be idempotent: a crash after Up
+// but before the migration is recorded runs it again on the next attempt, and
+// so do two instances migrating at the same time.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+}
+
+// Record is a migration that has been applied.
+type Record struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"applied_at"`
+	DurationMS  int64     `bson:"duration_ms"`
+}
+
+// Status is a known migration and, once applied, its record.
+type Status struct {
+	Migration
+	Applied *Record
+}
+
+// Store keeps the applied migrations.
+type Store interface {
+	Applied(ctx context.Context) (map[int]Record, error)
+	Save(ctx context.Context, record Record) error
+}
+
+type Runner struct {
+	db         *mongo.Database
+	store      Store
+	migrations []Migration
+}
+
+// NewRunner returns a Runner for migrations, which must have distinct
+// positive versions, recording them in store.
+func NewRunner(db *mongo.Database, store Store, migrations []Migration) (*Runner, error) {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	for i, m := range sorted {
+		if m.Version <= 0 {
+			return nil, fmt.Errorf("migration %q: version must be positive", m.Description)
+		}
+		if i > 0 && sorted[i-1].Version == m.Version {
+			return nil, fmt.Errorf("migration version %d is used twice", m.Version)
+		}
+	}
+	return &Runner{db: db, store: store, migrations: sorted}, nil
+}
+
+// Up applies the pending migrations in version order and returns how many it
+// applied. It stops at the first failure; later migrations are left pending.
+func (r *Runner) Up(ctx context.Context) (int, error) {
+	applied, err := r.store.Applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, m := range r.migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+
+		start := time.Now()
+		if err := m.Up(ctx, r.db); err != nil {
+			return count, fmt.Errorf("migration %d (%s): %w", m.Version, m.Description, err)
+		}
+		record := Record{
+			Version:     m.Version,
+			Description: m.Description,
+			AppliedAt:   time.Now().UTC(),
+			DurationMS:  time.Since(start).Milliseconds(),
+		}
+		if err := r.store.Save(ctx, record); err != nil {
+			return count, fmt.Errorf("record migration %d: %w", m.Version, err)
+		}
+		slog.InfoContext(ctx, "migration applied", "version", m.Version, "description", m.Description, "duration_ms", record.DurationMS)
+		count++
+	}
+	return count, nil
+}
+
+// Status lists every known migration with its record, if applied.
+func (r *Runner) Status(ctx context.Context) ([]Status, error) {
+	applied, err := r.store.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(r.migrations))
+	for i, m := range r.migrations {
+		statuses[i] = Status{Migration: m}
+		if record, ok := applied[m.Version]; ok {
+			statuses[i].Applied = &record
+		}
+	}
+	return statuses, nil
+}
+
+type mongoStore struct {
+	collection *mongo.Collection
+}
+
+// NewMongoStore records migrations in collection, normally CollectionName.
+func NewMongoStore(collection *mongo.Collection) Store {
+	return &mongoStore{collection: collection}
+}
+
+func (s *mongoStore) Applied(ctx context.Context) (map[int]Record, error) {
+	cursor, err := s.collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var records []Record
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+	applied := make(map[int]Record, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// Save upserts, so an instance racing another one to the same migration
+// does not fail.
+func (s *mongoStore) Save(ctx context.Context, record Record) error {
+	_, err := s.collection.ReplaceOne(ctx, bson.M{"_id": record.Version}, record, options.Replace().SetUpsert(true))
+	return err
+}
@@ -0,0 +1,93 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type memoryStore struct {
+	records map[int]Record
+}
+
+func (s *memoryStore) Applied(ctx context.Context) (map[int]Record, error) {
+	out := make(map[int]Record, len(s.records))
+	for v, r := range s.records {
+		out[v] = r
+	}
+	return out, nil
+}
+
+func (s *memoryStore) Save(ctx context.Context, record Record) error {
+	s.records[record.Version] = record
+	return nil
+}
+
+func TestRunner(t *testing.T) {
+	var ran []int
+	step := func(version int, err error) Migration {
+		return Migration{Version: version, Description: "step", Up: func(ctx context.Context, db *mongo.Database) error {
+			ran = append(ran, version)
+			return err
+		}}
+	}
+	ctx := context.Background()
+
+	t.Run("Applies Pending In Order", func(t *testing.T) {
+		ran = nil
+		store := &memoryStore{records: map[int]Record{1: {Version: 1}}}
+		runner, err := NewRunner(nil, store, []Migration{step(3, nil), step(1, nil), step(2, nil)})
+		require.NoError(t, err)
+
+		n, err := runner.Up(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
+		assert.Equal(t, []int{2, 3}, ran)
+		assert.Contains(t, store.records, 3)
+
+		n, err = runner.Up(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+		assert.Equal(t, []int{2, 3}, ran)
+	})
+
+	t.Run("Stops At Failure", func(t *testing.T) {
+		ran = nil
+		store := &memoryStore{records: map[int]Record{}}
+		runner, err := NewRunner(nil, store, []Migration{step(1, nil), step(2, errors.New("boom")), step(3, nil)})
+		require.NoError(t, err)
+
+		n, err := runner.Up(ctx)
+
+		assert.EqualError(t, err, "migration 2 (step): boom")
+		assert.Equal(t, 1, n)
+		assert.Equal(t, []int{1, 2}, ran)
+		assert.NotContains(t, store.records, 2)
+	})
+
+	t.Run("Status", func(t *testing.T) {
+		store := &memoryStore{records: map[int]Record{1: {Version: 1}}}
+		runner, err := NewRunner(nil, store, []Migration{step(1, nil), step(2, nil)})
+		require.NoError(t, err)
+
+		statuses, err := runner.Status(ctx)
+
+		require.NoError(t, err)
+		require.Len(t, statuses, 2)
+		assert.NotNil(t, statuses[0].Applied)
+		assert.Nil(t, statuses[1].Applied)
+	})
+
+	t.Run("Invalid Versions", func(t *testing.T) {
+		_, err := NewRunner(nil, &memoryStore{}, []Migration{step(1, nil), step(1, nil)})
+		assert.EqualError(t, err, "migration version 1 is used twice")
+
+		_, err = NewRunner(nil, &memoryStore{}, []Migration{step(0, nil)})
+		assert.EqualError(t, err, `migration "step": version must be positive`)
+	})
+}
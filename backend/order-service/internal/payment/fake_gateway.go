@@ -0,0 +1,110 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/yourusername/ecommerce/pkg/money"
+	"order-service/internal/domain"
+)
+
+// Amounts whose last two minor-unit digits select a failure in FakeGateway,
+// so any caller can provoke one without configuration.
+const (
+	FakeDeclineCents     = 51
+	FakeUnavailableCents = 52
+)
+
+const fakeReferencePrefix = "fake_"
+
+type fakeAuthorization struct {
+	amount money.Money
+	status domain.PaymentStatus
+}
+
+// FakeGateway is a deterministic in-process domain.PaymentGateway for tests
+// and local runs. It approves every authorization except those for amounts
+// ending in .51 (declined) or .52 (provider unavailable). References are
+// derived from the order ID, so authorizing an order twice is harmless.
+//
+// Authorizations live in memory and are lost on restart, while payment
+// records are not. A reference the gateway does not know is therefore taken
+// to be in whatever state the caller expects, as the stored payment says.
+type FakeGateway struct {
+	mu             sync.Mutex
+	authorizations map[string]*fakeAuthorization
+}
+
+func NewFakeGateway() *FakeGateway {
+	return &FakeGateway{authorizations: make(map[string]*fakeAuthorization)}
+}
+
+func (g *FakeGateway) Authorize(ctx context.Context, req domain.PaymentRequest) (string, error) {
+	switch req.Amount.Amount() % 100 {
+	case FakeDeclineCents:
+		return "", domain.ErrPaymentDeclined
+	case FakeUnavailableCents:
+		return "", domain.ErrPaymentUnavailable
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	reference := fakeReferencePrefix + req.OrderID.Hex()
+	if _, ok := g.authorizations[reference]; !ok {
+		g.authorizations[reference] = &fakeAuthorization{amount: req.Amount, status: domain.PaymentAuthorized}
+	}
+	return reference, nil
+}
+
+func (g *FakeGateway) Capture(ctx context.Context, reference string, amount money.Money) error {
+	return g.move(reference, amount, domain.PaymentAuthorized, domain.PaymentCaptured)
+}
+
+func (g *FakeGateway) Void(ctx context.Context, reference string) error {
+	return g.move(reference, money.Money{}, domain.PaymentAuthorized, domain.PaymentVoided)
+}
+
+func (g *FakeGateway) Refund(ctx context.Context, reference string, amount money.Money) error {
+	return g.move(reference, amount, domain.PaymentCaptured, domain.PaymentRefunded)
+}
+
+// Status returns the status of an authorization, or "" if it is unknown.
+func (g *FakeGateway) Status(reference string) domain.PaymentStatus {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if auth, ok := g.authorizations[reference]; ok {
+		return auth.status
+	}
+	return ""
+}
+
+// move changes an authorization from one status to another. Repeating a
+// move that already happened succeeds, as it would with a real provider
+// given the same idempotency key.
+func (g *FakeGateway) move(reference string, amount money.Money, from, to domain.PaymentStatus) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	auth, ok := g.authorizations[reference]
+	if !ok {
+		if !strings.HasPrefix(reference, fakeReferencePrefix) {
+			return fmt.Errorf("%w: unknown reference %s", domain.ErrPaymentClosed, reference)
+		}
+		// Authorized before a restart.
+		auth = &fakeAuthorization{amount: amount, status: from}
+		g.authorizations[reference] = auth
+	}
+	switch {
+	case auth.status == to:
+		return nil
+	case auth.status != from:
+		return domain.ErrPaymentClosed
+	}
+	if cmp, err := amount.Cmp(auth.amount); err != nil || cmp > 0 {
+		return fmt.Errorf("%w: amount exceeds authorization", domain.ErrPaymentDeclined)
+	}
+	auth.status = to
+	return nil
+}
@@ -0,0 +1,150 @@
+// Package payment takes payment for orders through a domain.PaymentGateway
+// and keeps a payment record per order alongside the orders.
+package payment
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/yourusername/ecommerce/pkg/money"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"order-service/internal/domain"
+)
+
+type Service struct {
+	gateway  domain.PaymentGateway
+	payments domain.PaymentRepository
+}
+
+func NewService(gateway domain.PaymentGateway, payments domain.PaymentRepository) *Service {
+	return &Service{
+		gateway:  gateway,
+		payments: payments,
+	}
+}
+
+// Authorize holds the order total. An order that already has an open payment
+// gets that payment back.
+func (s *Service) Authorize(ctx context.Context, order *domain.Order) (*domain.Payment, error) {
+	existing, err := s.payments.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return reuse(existing)
+	}
+
+	reference, err := s.gateway.Authorize(ctx, domain.PaymentRequest{OrderID: order.ID, Amount: order.TotalPrice})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	payment := &domain.Payment{
+		OrderID:   order.ID,
+		Reference: reference,
+		Amount:    order.TotalPrice,
+		Status:    domain.PaymentAuthorized,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.payments.Create(ctx, payment); err != nil {
+		// A concurrent Authorize for the order may have stored its payment
+		// first; the gateway gave both the same hold, which must be kept.
+		if existing, getErr := s.payments.GetByOrderID(ctx, order.ID); getErr == nil && existing != nil {
+			return reuse(existing)
+		}
+		// Otherwise nothing would ever void the hold.
+		if voidErr := s.gateway.Void(context.WithoutCancel(ctx), reference); voidErr != nil {
+			slog.ErrorContext(ctx, "void unrecorded authorization", "order_id", order.ID.Hex(), "reference", reference, "error", voidErr)
+		}
+		return nil, err
+	}
+	return payment, nil
+}
+
+// reuse returns an order's existing payment to Authorize if it is still open.
+func reuse(existing *domain.Payment) (*domain.Payment, error) {
+	if existing.Status == domain.PaymentAuthorized || existing.Status == domain.PaymentCaptured {
+		return existing, nil
+	}
+	return nil, domain.ErrPaymentClosed
+}
+
+func (s *Service) Capture(ctx context.Context, orderID primitive.ObjectID, amount money.Money) (*domain.Payment, error) {
+	payment, err := s.Get(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	switch payment.Status {
+	case domain.PaymentCaptured:
+		return payment, nil
+	case domain.PaymentAuthorized:
+	default:
+		return nil, domain.ErrPaymentClosed
+	}
+	if payment.Amount != amount {
+		return nil, domain.ErrPaymentMismatch
+	}
+
+	if err := s.gateway.Capture(ctx, payment.Reference, payment.Amount); err != nil {
+		return nil, err
+	}
+	return payment, s.setStatus(ctx, payment, domain.PaymentCaptured)
+}
+
+func (s *Service) Void(ctx context.Context, orderID primitive.ObjectID) error {
+	payment, err := s.payments.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if payment == nil || payment.Status == domain.PaymentVoided {
+		return nil
+	}
+	if payment.Status != domain.PaymentAuthorized {
+		return domain.ErrPaymentClosed
+	}
+
+	if err := s.gateway.Void(ctx, payment.Reference); err != nil {
+		return err
+	}
+	return s.setStatus(ctx, payment, domain.PaymentVoided)
+}
+
+func (s *Service) Refund(ctx context.Context, orderID primitive.ObjectID) (*domain.Payment, error) {
+	payment, err := s.Get(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	switch payment.Status {
+	case domain.PaymentRefunded:
+		return payment, nil
+	case domain.PaymentCaptured:
+	default:
+		return nil, domain.ErrPaymentClosed
+	}
+
+	if err := s.gateway.Refund(ctx, payment.Reference, payment.Amount); err != nil {
+		return nil, err
+	}
+	return payment, s.setStatus(ctx, payment, domain.PaymentRefunded)
+}
+
+// Get returns the order's payment, or ErrPaymentNotFound if it has none.
+func (s *Service) Get(ctx context.Context, orderID primitive.ObjectID) (*domain.Payment, error) {
+	payment, err := s.payments.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil {
+		return nil, domain.ErrPaymentNotFound
+	}
+	return payment, nil
+}
+
+func (s *Service) setStatus(ctx context.Context, payment *domain.Payment, status domain.PaymentStatus) error {
+	payment.Status = status
+	payment.UpdatedAt = time.Now()
+	return s.payments.Update(ctx, payment)
+}
@@ -0,0 +1,155 @@
+package payment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/ecommerce/pkg/money"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"order-service/internal/domain"
+	"order-service/internal/repository/memory"
+)
+
+// unstoredPayments fails every Create, as if the database were down.
+type unstoredPayments struct {
+	*memory.PaymentRepository
+}
+
+func (unstoredPayments) Create(ctx context.Context, payment *domain.Payment) error {
+	return assert.AnError
+}
+
+func TestService(t *testing.T) {
+	gateway := NewFakeGateway()
+	service := NewService(gateway, memory.NewPaymentRepository())
+	ctx := context.Background()
+
+	newOrder := func(cents int64) *domain.Order {
+		return &domain.Order{ID: primitive.NewObjectID(), TotalPrice: money.New(cents, "USD")}
+	}
+
+	t.Run("Authorize Capture Refund", func(t *testing.T) {
+		order := newOrder(125000)
+
+		payment, err := service.Authorize(ctx, order)
+		assert.NoError(t, err)
+		assert.Equal(t, order.ID, payment.OrderID)
+		assert.Equal(t, money.New(125000, "USD"), payment.Amount)
+		assert.Equal(t, domain.PaymentAuthorized, payment.Status)
+		assert.Equal(t, domain.PaymentAuthorized, gateway.Status(payment.Reference))
+
+		payment, err = service.Capture(ctx, order.ID, order.TotalPrice)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.PaymentCaptured, payment.Status)
+		assert.Equal(t, domain.PaymentCaptured, gateway.Status(payment.Reference))
+
+		payment, err = service.Refund(ctx, order.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.PaymentRefunded, payment.Status)
+		assert.Equal(t, domain.PaymentRefunded, gateway.Status(payment.Reference))
+	})
+
+	t.Run("Authorize Is Idempotent", func(t *testing.T) {
+		order := newOrder(10000)
+
+		first, err := service.Authorize(ctx, order)
+		assert.NoError(t, err)
+		second, err := service.Authorize(ctx, order)
+		assert.NoError(t, err)
+
+		assert.Equal(t, first.ID, second.ID)
+	})
+
+	t.Run("Declined", func(t *testing.T) {
+		order := newOrder(1051)
+
+		_, err := service.Authorize(ctx, order)
+
+		assert.ErrorIs(t, err, domain.ErrPaymentDeclined)
+		_, err = service.Get(ctx, order.ID)
+		assert.ErrorIs(t, err, domain.ErrPaymentNotFound)
+	})
+
+	t.Run("Provider Unavailable", func(t *testing.T) {
+		_, err := service.Authorize(ctx, newOrder(1052))
+
+		assert.ErrorIs(t, err, domain.ErrPaymentUnavailable)
+	})
+
+	t.Run("Voids Hold When Record Fails", func(t *testing.T) {
+		order := newOrder(10000)
+		unstored := NewService(gateway, unstoredPayments{memory.NewPaymentRepository()})
+
+		_, err := unstored.Authorize(ctx, order)
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, domain.PaymentVoided, gateway.Status("fake_"+order.ID.Hex()))
+	})
+
+	t.Run("Survives Gateway Restart", func(t *testing.T) {
+		payments := memory.NewPaymentRepository()
+		order := newOrder(10000)
+		_, err := NewService(NewFakeGateway(), payments).Authorize(ctx, order)
+		assert.NoError(t, err)
+
+		restarted := NewService(NewFakeGateway(), payments)
+		payment, err := restarted.Capture(ctx, order.ID, order.TotalPrice)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.PaymentCaptured, payment.Status)
+		_, err = restarted.Refund(ctx, order.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Void", func(t *testing.T) {
+		order := newOrder(10000)
+		payment, _ := service.Authorize(ctx, order)
+
+		assert.NoError(t, service.Void(ctx, order.ID))
+		assert.NoError(t, service.Void(ctx, order.ID))
+		assert.Equal(t, domain.PaymentVoided, gateway.Status(payment.Reference))
+
+		_, err := service.Capture(ctx, order.ID, order.TotalPrice)
+		assert.ErrorIs(t, err, domain.ErrPaymentClosed)
+		_, err = service.Authorize(ctx, order)
+		assert.ErrorIs(t, err, domain.ErrPaymentClosed)
+	})
+
+	t.Run("Void Without Payment", func(t *testing.T) {
+		assert.NoError(t, service.Void(ctx, primitive.NewObjectID()))
+	})
+
+	t.Run("Captured Cannot Be Voided", func(t *testing.T) {
+		order := newOrder(10000)
+		service.Authorize(ctx, order)
+		service.Capture(ctx, order.ID, order.TotalPrice)
+
+		assert.ErrorIs(t, service.Void(ctx, order.ID), domain.ErrPaymentClosed)
+	})
+
+	t.Run("Refund Needs Capture", func(t *testing.T) {
+		order := newOrder(10000)
+		service.Authorize(ctx, order)
+
+		_, err := service.Refund(ctx, order.ID)
+
+		assert.ErrorIs(t, err, domain.ErrPaymentClosed)
+	})
+
+	t.Run("Capture Of Another Amount", func(t *testing.T) {
+		order := newOrder(10000)
+		payment, _ := service.Authorize(ctx, order)
+
+		_, err := service.Capture(ctx, order.ID, money.New(15000, "USD"))
+
+		assert.ErrorIs(t, err, domain.ErrPaymentMismatch)
+		assert.Equal(t, domain.PaymentAuthorized, gateway.Status(payment.Reference))
+	})
+
+	t.Run("Capture Without Payment", func(t *testing.T) {
+		_, err := service.Capture(ctx, primitive.NewObjectID(), money.New(10000, "USD"))
+
+		assert.ErrorIs(t, err, domain.ErrPaymentNotFound)
+	})
+}
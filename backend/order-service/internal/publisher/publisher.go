@@ -0,0 +1,56 @@
+// Package publisher holds domain.EventPublisher implementations that need no
+// message broker: a logger for local runs and an in-memory recorder for tests.
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+
+	"order-service/internal/domain"
+)
+
+type LogPublisher struct {
+	logger *slog.Logger
+}
+
+func NewLogPublisher(logger *slog.Logger) *LogPublisher {
+	return &LogPublisher{logger: logger}
+}
+
+func (p *LogPublisher) Publish(ctx context.Context, event domain.OrderEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	p.logger.InfoContext(ctx, "event published", "type", event.Type, "event", json.RawMessage(body))
+	return nil
+}
+
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []domain.OrderEvent
+	// Err, when set, is returned by Publish instead of recording the event.
+	Err error
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (p *MemoryPublisher) Publish(ctx context.Context, event domain.OrderEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.Err != nil {
+		return p.Err
+	}
+	p.events = append(p.events, event)
+	return nil
+}
+
+func (p *MemoryPublisher) Events() []domain.OrderEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]domain.OrderEvent(nil), p.events...)
+}
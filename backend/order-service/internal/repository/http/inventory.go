@@ -0,0 +1,144 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"order-service/internal/domain"
+)
+
+type httpInventory struct {
+	baseURL string
+	client  *http.Client
+	ttl     time.Duration
+}
+
+type reservationItem struct {
+	ProductID string `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+}
+
+type reservationRequest struct {
+	OrderID    string            `json:"order_id"`
+	Items      []reservationItem `json:"items"`
+	TTLSeconds int               `json:"ttl_seconds,omitempty"`
+}
+
+// NewHTTPInventory talks to the product service's reservation API. A ttl of
+// zero leaves the expiry to the product service's default.
+func NewHTTPInventory(baseURL string, client *http.Client, ttl time.Duration) domain.Inventory {
+	return &httpInventory{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  client,
+		ttl:     ttl,
+	}
+}
+
+func (i *httpInventory) Reserve(ctx context.Context, orderID primitive.ObjectID, items []domain.OrderItem) error {
+	body := reservationRequest{OrderID: orderID.Hex(), TTLSeconds: int(i.ttl / time.Second)}
+	for _, item := range items {
+		body.Items = append(body.Items, reservationItem{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	status, failure, err := i.post(ctx, "/api/v1/reservations", payload)
+	if err != nil {
+		return err
+	}
+	switch {
+	case status == http.StatusCreated, status == http.StatusOK:
+		return nil
+	case status == http.StatusConflict && failure.Code == codeReservationClosed:
+		// The order's reservation was already committed or released.
+		return upstreamError(domain.ErrReservationClosed, failure.Error)
+	case status == http.StatusConflict:
+		return upstreamError(domain.ErrInsufficientStock, failure.Error)
+	case status == http.StatusBadRequest:
+		return upstreamError(domain.ErrInvalidOrderItems, failure.Error)
+	default:
+		return fmt.Errorf("%w: product service returned %d", domain.ErrInventoryUnavailable, status)
+	}
+}
+
+func (i *httpInventory) Commit(ctx context.Context, orderID primitive.ObjectID) error {
+	return i.settle(ctx, orderID, "commit")
+}
+
+func (i *httpInventory) Reopen(ctx context.Context, orderID primitive.ObjectID) error {
+	return i.settle(ctx, orderID, "reopen")
+}
+
+func (i *httpInventory) Release(ctx context.Context, orderID primitive.ObjectID) error {
+	return i.settle(ctx, orderID, "release")
+}
+
+// settle commits, reopens or releases a reservation. Orders placed before stock was
+// tracked have no reservation, so a 404 means there is nothing to settle.
+func (i *httpInventory) settle(ctx context.Context, orderID primitive.ObjectID, action string) error {
+	status, failure, err := i.post(ctx, "/api/v1/reservations/"+orderID.Hex()+"/"+action, nil)
+	if err != nil {
+		return err
+	}
+	switch status {
+	case http.StatusOK, http.StatusNotFound:
+		return nil
+	case http.StatusConflict:
+		return upstreamError(domain.ErrReservationClosed, failure.Error)
+	default:
+		return fmt.Errorf("%w: product service returned %d", domain.ErrInventoryUnavailable, status)
+	}
+}
+
+// codeReservationClosed is the product service's code for a 409 on a
+// reservation that has been committed or released.
+const codeReservationClosed = "reservation_closed"
+
+// upstreamFailure is the product service's {"error": "...", "code": "..."}
+// error body.
+type upstreamFailure struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// post sends payload and returns the status code along with the product
+// service's error body, if any.
+func (i *httpInventory) post(ctx context.Context, path string, payload []byte) (int, upstreamFailure, error) {
+	var failure upstreamFailure
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, i.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return 0, failure, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return 0, failure, fmt.Errorf("%w: %v", domain.ErrInventoryUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		_ = json.Unmarshal(data, &failure)
+	}
+	return resp.StatusCode, failure, nil
+}
+
+func upstreamError(err error, message string) error {
+	if message == "" {
+		return err
+	}
+	return fmt.Errorf("%w: %s", err, message)
+}
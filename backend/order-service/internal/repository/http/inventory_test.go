@@ -0,0 +1,103 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"order-service/internal/domain"
+)
+
+func TestInventory(t *testing.T) {
+	reserved := primitive.NewObjectID()
+	short := primitive.NewObjectID()
+	legacy := primitive.NewObjectID()
+	committed := primitive.NewObjectID()
+
+	var received reservationRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/reservations":
+			json.NewDecoder(r.Body).Decode(&received)
+			if received.OrderID == short.Hex() {
+				w.WriteHeader(http.StatusConflict)
+				w.Write([]byte(`{"error":"insufficient stock: product 456","code":"insufficient_stock"}`))
+				return
+			}
+			if received.OrderID == committed.Hex() {
+				w.WriteHeader(http.StatusConflict)
+				w.Write([]byte(`{"error":"reservation is no longer open","code":"reservation_closed"}`))
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		case "/api/v1/reservations/" + reserved.Hex() + "/commit", "/api/v1/reservations/" + reserved.Hex() + "/reopen",
+			"/api/v1/reservations/" + reserved.Hex() + "/release":
+			w.WriteHeader(http.StatusOK)
+		case "/api/v1/reservations/" + short.Hex() + "/commit":
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"error":"reservation is no longer open"}`))
+		case "/api/v1/reservations/" + legacy.Hex() + "/release":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	inventory := NewHTTPInventory(server.URL+"/", server.Client(), 10*time.Minute)
+	items := []domain.OrderItem{{ProductID: "456", Quantity: 2}}
+
+	t.Run("Reserve", func(t *testing.T) {
+		err := inventory.Reserve(context.Background(), reserved, items)
+
+		assert.NoError(t, err)
+		assert.Equal(t, reservationRequest{
+			OrderID:    reserved.Hex(),
+			Items:      []reservationItem{{ProductID: "456", Quantity: 2}},
+			TTLSeconds: 600,
+		}, received)
+	})
+
+	t.Run("Insufficient Stock", func(t *testing.T) {
+		err := inventory.Reserve(context.Background(), short, items)
+
+		assert.ErrorIs(t, err, domain.ErrInsufficientStock)
+		assert.Contains(t, err.Error(), "product 456")
+	})
+
+	t.Run("Reserve Closed", func(t *testing.T) {
+		err := inventory.Reserve(context.Background(), committed, items)
+
+		assert.ErrorIs(t, err, domain.ErrReservationClosed)
+		assert.NotErrorIs(t, err, domain.ErrInsufficientStock)
+	})
+
+	t.Run("Commit", func(t *testing.T) {
+		assert.NoError(t, inventory.Commit(context.Background(), reserved))
+	})
+
+	t.Run("Commit Closed", func(t *testing.T) {
+		err := inventory.Commit(context.Background(), short)
+
+		assert.ErrorIs(t, err, domain.ErrReservationClosed)
+	})
+
+	t.Run("Reopen", func(t *testing.T) {
+		assert.NoError(t, inventory.Reopen(context.Background(), reserved))
+	})
+
+	t.Run("Release Without Reservation", func(t *testing.T) {
+		assert.NoError(t, inventory.Release(context.Background(), legacy))
+	})
+
+	t.Run("Upstream Error", func(t *testing.T) {
+		err := inventory.Commit(context.Background(), primitive.NewObjectID())
+
+		assert.ErrorIs(t, err, domain.ErrInventoryUnavailable)
+	})
+}
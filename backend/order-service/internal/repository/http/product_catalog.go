@@ -0,0 +1,51 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"order-service/internal/domain"
+)
+
+type httpProductCatalog struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewHTTPProductCatalog(baseURL string, client *http.Client) domain.ProductCatalog {
+	return &httpProductCatalog{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  client,
+	}
+}
+
+func (c *httpProductCatalog) GetProduct(ctx context.Context, id string) (*domain.CatalogProduct, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/products/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrCatalogUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", domain.ErrProductNotFound, id)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%w: product service returned %d", domain.ErrCatalogUnavailable, resp.StatusCode)
+	}
+
+	var product domain.CatalogProduct
+	if err := json.NewDecoder(resp.Body).Decode(&product); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrCatalogUnavailable, err)
+	}
+	return &product, nil
+}
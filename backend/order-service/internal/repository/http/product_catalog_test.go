@@ -0,0 +1,50 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/ecommerce/pkg/money"
+	"order-service/internal/domain"
+)
+
+func TestGetProduct(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/products/456":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id":"456","sku":"SKU-456","name":"Keyboard","price":{"amount":"500.00","currency":"USD"},"active":true}`))
+		case "/api/v1/products/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	catalog := NewHTTPProductCatalog(server.URL+"/", server.Client())
+
+	t.Run("Success", func(t *testing.T) {
+		product, err := catalog.GetProduct(context.Background(), "456")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.CatalogProduct{ID: "456", SKU: "SKU-456", Name: "Keyboard", Price: money.New(50000, "USD"), Active: true}, product)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		product, err := catalog.GetProduct(context.Background(), "999")
+
+		assert.ErrorIs(t, err, domain.ErrProductNotFound)
+		assert.Nil(t, product)
+	})
+
+	t.Run("Upstream Error", func(t *testing.T) {
+		product, err := catalog.GetProduct(context.Background(), "broken")
+
+		assert.ErrorIs(t, err, domain.ErrCatalogUnavailable)
+		assert.Nil(t, product)
+	})
+}
@@ -0,0 +1,45 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"order-service/internal/domain"
+)
+
+// serviceTokenTTL keeps the signed tokens short-lived; each request gets a
+// fresh one, so it only has to outlast a single call.
+const serviceTokenTTL = time.Minute
+
+type serviceTokenTransport struct {
+	base    http.RoundTripper
+	subject string
+	secret  []byte
+}
+
+// NewServiceTokenTransport signs every request with an HS256 bearer token for
+// subject carrying the service role, which the product service requires for
+// its reservation endpoints.
+func NewServiceTokenTransport(base http.RoundTripper, subject, secret string) http.RoundTripper {
+	return &serviceTokenTransport{base: base, subject: subject, secret: []byte(secret)}
+}
+
+func (t *serviceTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	now := time.Now()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":  t.subject,
+		"role": domain.RoleService,
+		"iat":  now.Unix(),
+		"exp":  now.Add(serviceTokenTTL).Unix(),
+	}).SignedString(t.secret)
+	if err != nil {
+		return nil, fmt.Errorf("sign service token: %w", err)
+	}
+
+	// RoundTrippers must not modify the caller's request.
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"order-service/internal/domain"
+)
+
+func TestServiceTokenTransport(t *testing.T) {
+	var claims jwt.MapClaims
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenString := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		_, err := jwt.ParseWithClaims(tokenString, &claims, func(*jwt.Token) (interface{}, error) {
+			return []byte("secret"), nil
+		}, jwt.WithValidMethods([]string{"HS256"}), jwt.WithExpirationRequired())
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewServiceTokenTransport(http.DefaultTransport, "order-service", "secret")}
+	req, _ := http.NewRequest(http.MethodPost, server.URL, nil)
+	resp, err := client.Do(req)
+
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Empty(t, req.Header.Get("Authorization"))
+	assert.Equal(t, "order-service", claims["sub"])
+	assert.Equal(t, domain.RoleService, claims["role"])
+}
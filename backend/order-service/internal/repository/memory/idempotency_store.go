@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"order-service/internal/domain"
+)
+
+// IdempotencyStore is an in-memory domain.IdempotencyStore for tests. Records
+// never expire.
+type IdempotencyStore struct {
+	mu      sync.Mutex
+	records map[[2]string]domain.IdempotencyRecord
+}
+
+func NewIdempotencyStore() *IdempotencyStore {
+	return &IdempotencyStore{records: make(map[[2]string]domain.IdempotencyRecord)}
+}
+
+func (s *IdempotencyStore) Reserve(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := [2]string{record.UserID, record.Key}
+	if existing, ok := s.records[id]; ok {
+		return &existing, nil
+	}
+	s.records[id] = *record
+	return nil, nil
+}
+
+func (s *IdempotencyStore) Complete(ctx context.Context, record *domain.IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record.Completed = true
+	s.records[[2]string{record.UserID, record.Key}] = *record
+	return nil
+}
+
+func (s *IdempotencyStore) Release(ctx context.Context, userID, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, [2]string{userID, key})
+	return nil
+}
@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"order-service/internal/domain"
+)
+
+// Reservation statuses reported by Inventory.ReservationStatus.
+const (
+	ReservationReserved  = "reserved"
+	ReservationCommitted = "committed"
+	ReservationReleased  = "released"
+)
+
+type reservation struct {
+	items  map[string]int
+	status string
+}
+
+// Inventory is an in-memory domain.Inventory for tests and local runs
+// without a product service.
+type Inventory struct {
+	mu           sync.Mutex
+	available    map[string]int
+	reservations map[primitive.ObjectID]*reservation
+}
+
+func NewInventory() *Inventory {
+	return &Inventory{
+		available:    make(map[string]int),
+		reservations: make(map[primitive.ObjectID]*reservation),
+	}
+}
+
+// SetStock sets how many units of productID can still be reserved.
+func (i *Inventory) SetStock(productID string, available int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.available[productID] = available
+}
+
+// Available reports how many units of productID can still be reserved.
+func (i *Inventory) Available(productID string) int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.available[productID]
+}
+
+// ReservationStatus returns the status of the order's reservation, or "" if
+// it has none.
+func (i *Inventory) ReservationStatus(orderID primitive.ObjectID) string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if r, ok := i.reservations[orderID]; ok {
+		return r.status
+	}
+	return ""
+}
+
+func (i *Inventory) Reserve(ctx context.Context, orderID primitive.ObjectID, items []domain.OrderItem) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	wanted := make(map[string]int)
+	for _, item := range items {
+		wanted[item.ProductID] += item.Quantity
+	}
+
+	existing := i.reservations[orderID]
+	if existing != nil && existing.status == ReservationCommitted {
+		return domain.ErrReservationClosed
+	}
+	held := make(map[string]int)
+	if existing != nil && existing.status == ReservationReserved {
+		held = existing.items
+	}
+	for productID, quantity := range wanted {
+		if i.available[productID]+held[productID] < quantity {
+			return fmt.Errorf("%w: product %s", domain.ErrInsufficientStock, productID)
+		}
+	}
+
+	for productID, quantity := range held {
+		i.available[productID] += quantity
+	}
+	for productID, quantity := range wanted {
+		i.available[productID] -= quantity
+	}
+	i.reservations[orderID] = &reservation{items: wanted, status: ReservationReserved}
+	return nil
+}
+
+func (i *Inventory) Commit(ctx context.Context, orderID primitive.ObjectID) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	r, ok := i.reservations[orderID]
+	switch {
+	case !ok:
+		return nil
+	case r.status == ReservationReleased:
+		return domain.ErrReservationClosed
+	}
+	r.status = ReservationCommitted
+	return nil
+}
+
+func (i *Inventory) Reopen(ctx context.Context, orderID primitive.ObjectID) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	r, ok := i.reservations[orderID]
+	switch {
+	case !ok:
+		return nil
+	case r.status == ReservationReleased:
+		return domain.ErrReservationClosed
+	}
+	r.status = ReservationReserved
+	return nil
+}
+
+func (i *Inventory) Release(ctx context.Context, orderID primitive.ObjectID) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	r, ok := i.reservations[orderID]
+	switch {
+	case !ok || r.status == ReservationReleased:
+		return nil
+	case r.status == ReservationCommitted:
+		return domain.ErrReservationClosed
+	}
+	for productID, quantity := range r.items {
+		i.available[productID] += quantity
+	}
+	r.status = ReservationReleased
+	return nil
+}
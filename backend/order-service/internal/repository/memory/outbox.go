@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"order-service/internal/domain"
+)
+
+// Outbox is an in-memory domain.OutboxRepository for tests.
+type Outbox struct {
+	mu        sync.Mutex
+	events    []domain.OrderEvent
+	published map[primitive.ObjectID]bool
+}
+
+func NewOutbox() *Outbox {
+	return &Outbox{published: make(map[primitive.ObjectID]bool)}
+}
+
+func (o *Outbox) Add(ctx context.Context, events ...domain.OrderEvent) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, events...)
+	return nil
+}
+
+func (o *Outbox) Pending(ctx context.Context, limit int) ([]domain.OrderEvent, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var pending []domain.OrderEvent
+	for _, event := range o.events {
+		if len(pending) == limit {
+			break
+		}
+		if !o.published[event.ID] {
+			pending = append(pending, event)
+		}
+	}
+	return pending, nil
+}
+
+func (o *Outbox) MarkPublished(ctx context.Context, id primitive.ObjectID) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.published[id] = true
+	return nil
+}
+
+// Events returns every event added so far, published or not.
+func (o *Outbox) Events() []domain.OrderEvent {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]domain.OrderEvent(nil), o.events...)
+}
+
+// TxManager runs functions directly, without a transaction. It is meant for
+// tests where every repository is in memory or mocked.
+type TxManager struct{}
+
+func (TxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
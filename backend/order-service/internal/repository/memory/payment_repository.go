@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"order-service/internal/domain"
+)
+
+// PaymentRepository is an in-memory domain.PaymentRepository for tests and
+// local runs.
+type PaymentRepository struct {
+	mu       sync.Mutex
+	payments map[primitive.ObjectID]domain.Payment
+}
+
+func NewPaymentRepository() *PaymentRepository {
+	return &PaymentRepository{payments: make(map[primitive.ObjectID]domain.Payment)}
+}
+
+func (r *PaymentRepository) Create(ctx context.Context, payment *domain.Payment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if payment.ID.IsZero() {
+		payment.ID = primitive.NewObjectID()
+	}
+	r.payments[payment.OrderID] = *payment
+	return nil
+}
+
+func (r *PaymentRepository) Update(ctx context.Context, payment *domain.Payment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.payments[payment.OrderID] = *payment
+	return nil
+}
+
+func (r *PaymentRepository) GetByOrderID(ctx context.Context, orderID primitive.ObjectID) (*domain.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	payment, ok := r.payments[orderID]
+	if !ok {
+		return nil, nil
+	}
+	return &payment, nil
+}
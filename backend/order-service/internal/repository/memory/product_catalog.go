@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"order-service/internal/domain"
+)
+
+// ProductCatalog is an in-memory domain.ProductCatalog for tests and local runs
+// without a product service.
+type ProductCatalog struct {
+	mu       sync.RWMutex
+	products map[string]domain.CatalogProduct
+}
+
+func NewProductCatalog(products ...domain.CatalogProduct) *ProductCatalog {
+	c := &ProductCatalog{products: make(map[string]domain.CatalogProduct)}
+	for _, p := range products {
+		c.Put(p)
+	}
+	return c
+}
+
+func (c *ProductCatalog) Put(product domain.CatalogProduct) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.products[product.ID] = product
+}
+
+func (c *ProductCatalog) GetProduct(ctx context.Context, id string) (*domain.CatalogProduct, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	product, ok := c.products[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrProductNotFound, id)
+	}
+	return &product, nil
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"order-service/internal/domain"
+)
+
+// SagaRepository is an in-memory domain.SagaRepository for tests.
+type SagaRepository struct {
+	mu    sync.Mutex
+	sagas map[primitive.ObjectID]domain.CheckoutSaga
+}
+
+func NewSagaRepository() *SagaRepository {
+	return &SagaRepository{sagas: make(map[primitive.ObjectID]domain.CheckoutSaga)}
+}
+
+func (r *SagaRepository) Create(ctx context.Context, saga *domain.CheckoutSaga) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if saga.ID.IsZero() {
+		saga.ID = primitive.NewObjectID()
+	}
+	r.sagas[saga.OrderID] = cloneSaga(*saga)
+	return nil
+}
+
+func (r *SagaRepository) Update(ctx context.Context, saga *domain.CheckoutSaga) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.sagas[saga.OrderID]
+	if !ok || stored.Owner != saga.Owner || !stored.LeaseExpiresAt.After(time.Now()) {
+		return domain.ErrSagaLeaseLost
+	}
+	r.sagas[saga.OrderID] = cloneSaga(*saga)
+	return nil
+}
+
+func (r *SagaRepository) GetByOrderID(ctx context.Context, orderID primitive.ObjectID) (*domain.CheckoutSaga, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	saga, ok := r.sagas[orderID]
+	if !ok {
+		return nil, nil
+	}
+	saga = cloneSaga(saga)
+	return &saga, nil
+}
+
+func (r *SagaRepository) ClaimStalled(ctx context.Context, before time.Time, owner string, leaseUntil time.Time) (*domain.CheckoutSaga, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	var claimed *domain.CheckoutSaga
+	for _, saga := range r.sagas {
+		if saga.IsFinished() || !saga.UpdatedAt.Before(before) || saga.LeaseExpiresAt.After(now) {
+			continue
+		}
+		if claimed == nil || saga.UpdatedAt.Before(claimed.UpdatedAt) {
+			saga := saga
+			claimed = &saga
+		}
+	}
+	if claimed == nil {
+		return nil, nil
+	}
+
+	claimed.Owner = owner
+	claimed.LeaseExpiresAt = leaseUntil
+	r.sagas[claimed.OrderID] = cloneSaga(*claimed)
+	saga := cloneSaga(*claimed)
+	return &saga, nil
+}
+
+func cloneSaga(saga domain.CheckoutSaga) domain.CheckoutSaga {
+	saga.Completed = append([]domain.SagaStep(nil), saga.Completed...)
+	saga.Compensated = append([]domain.SagaStep(nil), saga.Compensated...)
+	return saga
+}
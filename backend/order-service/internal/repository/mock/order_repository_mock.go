@@ -4,8 +4,8 @@ import (
 	"context"
 
 	"github.com/stretchr/testify/mock"
-	"order-service/internal/domain"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"order-service/internal/domain"
 )
 
 type MockOrderRepository struct {
@@ -25,12 +25,12 @@ func (m *MockOrderRepository) GetByID(ctx context.Context, id primitive.ObjectID
 	return args.Get(0).(*domain.Order), args.Error(1)
 }
 
-func (m *MockOrderRepository) GetAll(ctx context.Context, userID string) ([]domain.Order, error) {
-	args := m.Called(ctx, userID)
+func (m *MockOrderRepository) GetAll(ctx context.Context, filter domain.OrderFilter) (*domain.OrderPage, error) {
+	args := m.Called(ctx, filter)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]domain.Order), args.Error(1)
+	return args.Get(0).(*domain.OrderPage), args.Error(1)
 }
 
 func (m *MockOrderRepository) Update(ctx context.Context, order *domain.Order) error {
@@ -38,7 +38,20 @@ func (m *MockOrderRepository) Update(ctx context.Context, order *domain.Order) e
 	return args.Error(0)
 }
 
-func (m *MockOrderRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
-	args := m.Called(ctx, id)
+func (m *MockOrderRepository) Patch(ctx context.Context, id primitive.ObjectID, patch domain.OrderPatch) (*domain.Order, error) {
+	args := m.Called(ctx, id, patch)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Order), args.Error(1)
+}
+
+func (m *MockOrderRepository) Delete(ctx context.Context, order *domain.Order) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+func (m *MockOrderRepository) Restore(ctx context.Context, order *domain.Order) error {
+	args := m.Called(ctx, order)
 	return args.Error(0)
 }
@@ -0,0 +1,76 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"order-service/internal/domain"
+)
+
+type mongoIdempotencyStore struct {
+	collection *mongo.Collection
+}
+
+func NewMongoIdempotencyStore(collection *mongo.Collection) domain.IdempotencyStore {
+	return &mongoIdempotencyStore{
+		collection: collection,
+	}
+}
+
+// EnsureIdempotencyIndexes creates the unique (user_id, key) index that makes
+// Reserve atomic and a TTL index that expires records after ttl.
+func EnsureIdempotencyIndexes(ctx context.Context, collection *mongo.Collection, ttl time.Duration) error {
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(ttl.Seconds())),
+		},
+	})
+	return err
+}
+
+func (s *mongoIdempotencyStore) Reserve(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	_, err := s.collection.InsertOne(ctx, record)
+	if err == nil {
+		return nil, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return nil, err
+	}
+
+	var existing domain.IdempotencyRecord
+	err = s.collection.FindOne(ctx, bson.M{"user_id": record.UserID, "key": record.Key}).Decode(&existing)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			// The record expired between the insert and the lookup.
+			return s.Reserve(ctx, record)
+		}
+		return nil, err
+	}
+	return &existing, nil
+}
+
+func (s *mongoIdempotencyStore) Complete(ctx context.Context, record *domain.IdempotencyRecord) error {
+	update := bson.M{
+		"$set": bson.M{
+			"completed":   true,
+			"status_code": record.StatusCode,
+			"header":      record.Header,
+			"body":        record.Body,
+		},
+	}
+	_, err := s.collection.UpdateOne(ctx, bson.M{"user_id": record.UserID, "key": record.Key}, update)
+	return err
+}
+
+func (s *mongoIdempotencyStore) Release(ctx context.Context, userID, key string) error {
+	_, err := s.collection.DeleteOne(ctx, bson.M{"user_id": userID, "key": key})
+	return err
+}
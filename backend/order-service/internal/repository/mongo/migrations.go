@@ -0,0 +1,176 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"order-service/internal/domain"
+	"order-service/internal/migration"
+)
+
+// Collections besides the orders one, whose name is configurable.
+const (
+	IdempotencyCollection = "idempotency_keys"
+	OutboxCollection      = "outbox"
+	SagaCollection        = "checkout_sagas"
+	PaymentCollection     = "payments"
+)
+
+// IdempotencyTTL is how long idempotency keys are kept. Changing it needs a
+// new migration that drops and recreates the TTL index.
+const IdempotencyTTL = 24 * time.Hour
+
+// codeNamespaceNotFound is returned by collMod on a missing collection.
+const codeNamespaceNotFound = 26
+
+// Migrations returns the order-service migrations, in version order. Never
+// edit or renumber one that has shipped; add a new version instead.
+func Migrations(ordersCollection string) []migration.Migration {
+	return []migration.Migration{
+		{
+			Version:     1,
+			Description: "rewrite float prices as money",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				n, err := MigrateLegacyOrders(ctx, db.Collection(ordersCollection))
+				if n > 0 {
+					slog.InfoContext(ctx, "migrated legacy order prices", "count", n)
+				}
+				return err
+			},
+		},
+		{
+			Version:     2,
+			Description: "indexes for idempotency keys, outbox, checkout sagas and payments",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				return errors.Join(
+					EnsureIdempotencyIndexes(ctx, db.Collection(IdempotencyCollection), IdempotencyTTL),
+					EnsureOutboxIndexes(ctx, db.Collection(OutboxCollection)),
+					EnsureSagaIndexes(ctx, db.Collection(SagaCollection)),
+					EnsurePaymentIndexes(ctx, db.Collection(PaymentCollection)),
+				)
+			},
+		},
+		{
+			Version:     3,
+			Description: "order indexes on user_id+created_at and status+created_at",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				return ensureOrderIndexes(ctx, db.Collection(ordersCollection))
+			},
+		},
+		{
+			Version:     4,
+			Description: "JSON schema validator on orders",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				return setValidator(ctx, db, ordersCollection, orderSchema())
+			},
+		},
+		{
+			// Version 1 used to keep only the prices of single-product
+			// orders, leaving them without items; it now converts them too,
+			// and this catches deployments where it already ran.
+			Version:     5,
+			Description: "turn single-product orders into items",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				n, err := MigrateLegacyOrders(ctx, db.Collection(ordersCollection))
+				if n > 0 {
+					slog.InfoContext(ctx, "migrated single-product orders", "count", n)
+				}
+				return err
+			},
+		},
+	}
+}
+
+// ensureOrderIndexes covers the listing queries: a user's orders newest
+// first, and orders in a status (e.g. the admin view of pending orders). _id
+// is the pagination tiebreaker.
+func ensureOrderIndexes(ctx context.Context, collection *mongo.Collection) error {
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("user_id_created_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("status_created_at"),
+		},
+	})
+	return err
+}
+
+// setValidator installs schema on the collection, creating it if needed.
+// Validation is "moderate": documents that already break the schema can still
+// be updated, but new and valid documents must stay valid.
+func setValidator(ctx context.Context, db *mongo.Database, name string, schema bson.M) error {
+	validator := bson.M{"$jsonSchema": schema}
+	err := db.RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: name},
+		{Key: "validator", Value: validator},
+		{Key: "validationLevel", Value: "moderate"},
+		{Key: "validationAction", Value: "error"},
+	}).Err()
+
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.HasErrorCode(codeNamespaceNotFound) {
+		return db.CreateCollection(ctx, name, options.CreateCollection().
+			SetValidator(validator).
+			SetValidationLevel("moderate").
+			SetValidationAction("error"))
+	}
+	return err
+}
+
+var (
+	integer   = bson.A{"int", "long"}
+	moneyType = bson.M{
+		"bsonType": "object",
+		"required": bson.A{"amount", "currency"},
+		"properties": bson.M{
+			"amount":   bson.M{"bsonType": integer},
+			"currency": bson.M{"bsonType": "string"},
+		},
+	}
+)
+
+// orderSchema describes the documents domain.Order is stored as.
+func orderSchema() bson.M {
+	statuses := bson.A{}
+	for _, s := range domain.OrderStatuses() {
+		statuses = append(statuses, string(s))
+	}
+
+	return bson.M{
+		"bsonType": "object",
+		"required": bson.A{"user_id", "items", "total_price", "status", "created_at", "updated_at"},
+		"properties": bson.M{
+			"user_id": bson.M{"bsonType": "string", "minLength": 1},
+			"items": bson.M{
+				"bsonType": "array",
+				"minItems": 1,
+				"items": bson.M{
+					"bsonType": "object",
+					"required": bson.A{"product_id", "quantity", "unit_price", "line_total"},
+					"properties": bson.M{
+						"product_id": bson.M{"bsonType": "string", "minLength": 1},
+						"sku":        bson.M{"bsonType": "string"},
+						"quantity":   bson.M{"bsonType": integer, "minimum": 1},
+						"unit_price": moneyType,
+						"line_total": moneyType,
+					},
+				},
+			},
+			"total_price": moneyType,
+			"status":      bson.M{"enum": statuses},
+			"created_at":  bson.M{"bsonType": "date"},
+			"updated_at":  bson.M{"bsonType": "date"},
+			"version":     bson.M{"bsonType": integer},
+			"deleted_at":  bson.M{"bsonType": "date"},
+			"deleted_by":  bson.M{"bsonType": "string"},
+		},
+	}
+}
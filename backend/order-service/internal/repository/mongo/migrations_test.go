@@ -0,0 +1,106 @@
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/ecommerce/pkg/money"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"order-service/internal/domain"
+	"order-service/internal/migration"
+)
+
+func TestMigrations(t *testing.T) {
+	_, err := migration.NewRunner(nil, nil, Migrations("orders"))
+	assert.NoError(t, err)
+}
+
+func TestLegacyOrderUpdate(t *testing.T) {
+	// An order as stored before items and Money, then with the update applied
+	// the way MongoDB would.
+	baseline := bson.M{
+		"_id":         primitive.NewObjectID(),
+		"user_id":     "123",
+		"product_id":  "456",
+		"quantity":    int32(2),
+		"total_price": 59.9,
+		"status":      "pending",
+		"created_at":  primitive.NewDateTimeFromTime(time.Now()),
+		"updated_at":  primitive.NewDateTimeFromTime(time.Now()),
+	}
+	raw, err := bson.Marshal(baseline)
+	require.NoError(t, err)
+	var order domain.Order
+	require.NoError(t, bson.Unmarshal(raw, &order))
+
+	update := legacyOrderUpdate(&order)
+	raw, err = bson.Marshal(update["$set"])
+	require.NoError(t, err)
+	var set bson.M
+	require.NoError(t, bson.Unmarshal(raw, &set))
+	for field, value := range set {
+		baseline[field] = value
+	}
+	for field := range update["$unset"].(bson.M) {
+		delete(baseline, field)
+	}
+
+	assert.NotContains(t, baseline, "product_id")
+	assert.NotContains(t, baseline, "quantity")
+	assert.Equal(t, bson.A{bson.M{
+		"product_id": "456",
+		"sku":        "",
+		"quantity":   int32(2),
+		"unit_price": bson.M{"amount": int64(2995), "currency": money.LegacyCurrency},
+		"line_total": bson.M{"amount": int64(5990), "currency": money.LegacyCurrency},
+	}}, baseline["items"])
+	assert.Equal(t, bson.M{"amount": int64(5990), "currency": money.LegacyCurrency}, baseline["total_price"])
+	for _, field := range orderSchema()["required"].(bson.A) {
+		assert.Contains(t, baseline, field)
+	}
+}
+
+// TestOrderSchemaMatchesDocuments guards against renaming an Order field
+// without a migration for the validator.
+func TestOrderSchemaMatchesDocuments(t *testing.T) {
+	order := domain.Order{
+		ID:     primitive.NewObjectID(),
+		UserID: "user-1",
+		Items: []domain.OrderItem{{
+			ProductID: "123", SKU: "SKU-123", Quantity: 2,
+			UnitPrice: money.New(1000, "USD"), LineTotal: money.New(2000, "USD"),
+		}},
+		TotalPrice: money.New(2000, "USD"),
+		Status:     domain.OrderStatusPending,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+		Version:    1,
+	}
+	raw, err := bson.Marshal(order)
+	require.NoError(t, err)
+	var doc bson.M
+	require.NoError(t, bson.Unmarshal(raw, &doc))
+
+	schema := orderSchema()
+	properties := schema["properties"].(bson.M)
+	for _, field := range schema["required"].(bson.A) {
+		assert.Contains(t, doc, field)
+	}
+	for field := range doc {
+		if field != "_id" {
+			assert.Contains(t, properties, field)
+		}
+	}
+
+	item := doc["items"].(bson.A)[0].(bson.M)
+	itemSchema := properties["items"].(bson.M)["items"].(bson.M)
+	for _, field := range itemSchema["required"].(bson.A) {
+		assert.Contains(t, item, field)
+	}
+	for field := range item {
+		assert.Contains(t, itemSchema["properties"], field)
+	}
+}
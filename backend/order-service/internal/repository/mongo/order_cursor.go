@@ -0,0 +1,60 @@
+package mongo
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"order-service/internal/domain"
+)
+
+// orderCursor is the position of the last order on a page. It is handed to
+// clients base64-encoded, so its layout can change without breaking the API.
+type orderCursor struct {
+	Sort      domain.OrderSort   `json:"s"`
+	ID        primitive.ObjectID `json:"id"`
+	CreatedAt time.Time          `json:"c,omitempty"`
+	// TotalPrice is the total in minor units, which is what orders sort by.
+	TotalPrice int64 `json:"p,omitempty"`
+}
+
+func newOrderCursor(sort domain.OrderSort, order domain.Order) orderCursor {
+	return orderCursor{
+		Sort:       sort,
+		ID:         order.ID,
+		CreatedAt:  order.CreatedAt,
+		TotalPrice: order.TotalPrice.Amount(),
+	}
+}
+
+func (c orderCursor) sortValue() interface{} {
+	if c.Sort.Field() == "total_price" {
+		return c.TotalPrice
+	}
+	return c.CreatedAt
+}
+
+func (c orderCursor) encode() (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeOrderCursor(s string, sort domain.OrderSort) (orderCursor, error) {
+	var c orderCursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, domain.ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &c); err != nil || c.ID.IsZero() {
+		return c, domain.ErrInvalidCursor
+	}
+	// A cursor only makes sense for the sort order it was issued under.
+	if c.Sort != sort {
+		return c, domain.ErrInvalidCursor
+	}
+	return c, nil
+}
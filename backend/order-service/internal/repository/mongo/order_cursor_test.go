@@ -0,0 +1,81 @@
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/ecommerce/pkg/money"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"order-service/internal/domain"
+)
+
+func TestOrderCursor(t *testing.T) {
+	order := domain.Order{
+		ID:         primitive.NewObjectID(),
+		TotalPrice: money.New(125000, "USD"),
+		CreatedAt:  time.Date(2024, 3, 6, 12, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("Round Trip", func(t *testing.T) {
+		encoded, err := newOrderCursor(domain.SortCreatedAtDesc, order).encode()
+		require.NoError(t, err)
+
+		decoded, err := decodeOrderCursor(encoded, domain.SortCreatedAtDesc)
+
+		assert.NoError(t, err)
+		assert.Equal(t, order.ID, decoded.ID)
+		assert.Equal(t, order.CreatedAt, decoded.sortValue())
+	})
+
+	t.Run("Different Sort", func(t *testing.T) {
+		encoded, err := newOrderCursor(domain.SortCreatedAtDesc, order).encode()
+		require.NoError(t, err)
+
+		_, err = decodeOrderCursor(encoded, domain.SortTotalPriceAsc)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+	})
+
+	t.Run("Garbage", func(t *testing.T) {
+		_, err := decodeOrderCursor("not a cursor!", domain.SortCreatedAtDesc)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+	})
+}
+
+func TestBuildOrderQuery(t *testing.T) {
+	min, max := money.New(10000, "USD"), money.New(50000, "USD")
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	last := domain.Order{ID: primitive.NewObjectID(), TotalPrice: money.New(30000, "USD")}
+	after, err := newOrderCursor(domain.SortTotalPriceDesc, last).encode()
+	require.NoError(t, err)
+
+	query, err := buildOrderQuery(domain.OrderFilter{
+		UserID:      "123",
+		Status:      domain.OrderStatusPaid,
+		ProductID:   "456",
+		CreatedFrom: &from,
+		MinTotal:    &min,
+		MaxTotal:    &max,
+		Sort:        domain.SortTotalPriceDesc,
+		After:       after,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, bson.M{
+		"deleted_at":           nil,
+		"user_id":              "123",
+		"status":               domain.OrderStatusPaid,
+		"items.product_id":     "456",
+		"created_at":           bson.M{"$gte": from},
+		"total_price.currency": "USD",
+		"total_price.amount":   bson.M{"$gte": int64(10000), "$lte": int64(50000)},
+		"$or": bson.A{
+			bson.M{"total_price.amount": bson.M{"$lt": int64(30000)}},
+			bson.M{"total_price.amount": int64(30000), "_id": bson.M{"$lt": last.ID}},
+		},
+	}, query)
+}
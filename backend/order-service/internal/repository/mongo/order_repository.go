@@ -6,6 +6,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"order-service/internal/domain"
 )
 
@@ -20,8 +21,14 @@ func NewMongoOrderRepository(collection *mongo.Collection) domain.OrderRepositor
 }
 
 func (r *mongoOrderRepository) Create(ctx context.Context, order *domain.Order) error {
-	_, err := r.collection.InsertOne(ctx, order)
-	return err
+	result, err := r.collection.InsertOne(ctx, order)
+	if err != nil {
+		return err
+	}
+	if id, ok := result.InsertedID.(primitive.ObjectID); ok {
+		order.ID = id
+	}
+	return nil
 }
 
 func (r *mongoOrderRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.Order, error) {
@@ -36,58 +43,244 @@ func (r *mongoOrderRepository) GetByID(ctx context.Context, id primitive.ObjectI
 	return &order, nil
 }
 
-func (r *mongoOrderRepository) GetAll(ctx context.Context, userID string) ([]domain.Order, error) {
-	filter := bson.M{}
-	if userID != "" {
-		filter["user_id"] = userID
+func (r *mongoOrderRepository) GetAll(ctx context.Context, filter domain.OrderFilter) (*domain.OrderPage, error) {
+	query, err := buildOrderQuery(filter)
+	if err != nil {
+		return nil, err
 	}
 
-	cursor, err := r.collection.Find(ctx, filter)
+	direction := 1
+	if filter.Sort.Descending() {
+		direction = -1
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: sortField(filter.Sort), Value: direction}, {Key: "_id", Value: direction}}).
+		SetLimit(int64(filter.Limit) + 1)
+
+	cursor, err := r.collection.Find(ctx, query, opts)
 	if err != nil {
 		return nil, err
 	}
 	defer cursor.Close(ctx)
 
-	var orders []domain.Order
+	orders := make([]domain.Order, 0, filter.Limit)
 	if err := cursor.All(ctx, &orders); err != nil {
 		return nil, err
 	}
 
-	return orders, nil
+	page := &domain.OrderPage{Orders: orders}
+	if len(orders) > filter.Limit {
+		page.Orders = orders[:filter.Limit]
+		next, err := newOrderCursor(filter.Sort, page.Orders[filter.Limit-1]).encode()
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = next
+	}
+
+	return page, nil
+}
+
+func buildOrderQuery(filter domain.OrderFilter) (bson.M, error) {
+	query := bson.M{}
+	if !filter.IncludeDeleted {
+		query["deleted_at"] = nil
+	}
+	if filter.UserID != "" {
+		query["user_id"] = filter.UserID
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if filter.ProductID != "" {
+		query["items.product_id"] = filter.ProductID
+	}
+
+	createdAt := bson.M{}
+	if filter.CreatedFrom != nil {
+		createdAt["$gte"] = *filter.CreatedFrom
+	}
+	if filter.CreatedTo != nil {
+		createdAt["$lt"] = *filter.CreatedTo
+	}
+	if len(createdAt) > 0 {
+		query["created_at"] = createdAt
+	}
+
+	totalPrice := bson.M{}
+	if filter.MinTotal != nil {
+		totalPrice["$gte"] = filter.MinTotal.Amount()
+		query["total_price.currency"] = filter.MinTotal.Currency()
+	}
+	if filter.MaxTotal != nil {
+		totalPrice["$lte"] = filter.MaxTotal.Amount()
+		query["total_price.currency"] = filter.MaxTotal.Currency()
+	}
+	if len(totalPrice) > 0 {
+		query["total_price.amount"] = totalPrice
+	}
+
+	if filter.After != "" {
+		after, err := decodeOrderCursor(filter.After, filter.Sort)
+		if err != nil {
+			return nil, err
+		}
+
+		op := "$gt"
+		if filter.Sort.Descending() {
+			op = "$lt"
+		}
+		field := sortField(filter.Sort)
+		query["$or"] = bson.A{
+			bson.M{field: bson.M{op: after.sortValue()}},
+			bson.M{field: after.sortValue(), "_id": bson.M{op: after.ID}},
+		}
+	}
+
+	return query, nil
+}
+
+// sortField is the document field an order sort is applied to. Totals sort
+// by their amount in minor units.
+func sortField(sort domain.OrderSort) string {
+	if sort.Field() == "total_price" {
+		return "total_price.amount"
+	}
+	return sort.Field()
 }
 
 func (r *mongoOrderRepository) Update(ctx context.Context, order *domain.Order) error {
 	update := bson.M{
 		"$set": bson.M{
-			"product_id":  order.ProductID,
-			"quantity":    order.Quantity,
+			"items":       order.Items,
 			"total_price": order.TotalPrice,
 			"status":      order.Status,
 			"updated_at":  order.UpdatedAt,
 		},
 	}
 
-	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": order.ID}, update)
+	return r.updateVersioned(ctx, order, update)
+}
+
+// updateVersioned applies update if the stored order is still at
+// order.Version and bumps the stored version. order itself is left alone: the
+// write may run inside a transaction that is retried or rolled back, so the
+// caller moves order.Version on once the write has committed.
+func (r *mongoOrderRepository) updateVersioned(ctx context.Context, order *domain.Order, update bson.M) error {
+	update["$inc"] = bson.M{"version": 1}
+
+	result, err := r.collection.UpdateOne(ctx, versionFilter(order.ID, order.Version), update)
 	if err != nil {
 		return err
 	}
 
 	if result.MatchedCount == 0 {
-		return mongo.ErrNoDocuments
+		return r.missingOrModified(ctx, order.ID)
 	}
-
 	return nil
 }
 
-func (r *mongoOrderRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+func (r *mongoOrderRepository) Patch(ctx context.Context, id primitive.ObjectID, patch domain.OrderPatch) (*domain.Order, error) {
+	set := bson.M{"updated_at": patch.UpdatedAt}
+	if patch.Items != nil {
+		set["items"] = patch.Items
+	}
+	if patch.TotalPrice != nil {
+		set["total_price"] = *patch.TotalPrice
+	}
+	if patch.Status != nil {
+		set["status"] = *patch.Status
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var order domain.Order
+	update := bson.M{"$set": set, "$inc": bson.M{"version": 1}}
+	err := r.collection.FindOneAndUpdate(ctx, versionFilter(id, patch.Version), update, opts).Decode(&order)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, r.missingOrModified(ctx, id)
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *mongoOrderRepository) Delete(ctx context.Context, order *domain.Order) error {
+	return r.updateVersioned(ctx, order, bson.M{
+		"$set": bson.M{
+			"deleted_at": order.DeletedAt,
+			"deleted_by": order.DeletedBy,
+			"status":     order.Status,
+			"updated_at": order.UpdatedAt,
+		},
+	})
+}
+
+func (r *mongoOrderRepository) Restore(ctx context.Context, order *domain.Order) error {
+	return r.updateVersioned(ctx, order, bson.M{
+		"$set":   bson.M{"status": order.Status, "updated_at": order.UpdatedAt},
+		"$unset": bson.M{"deleted_at": "", "deleted_by": ""},
+	})
+}
+
+// versionFilter matches the order only while it is still at version.
+// Documents written before versioning have no version field and count as 0.
+func versionFilter(id primitive.ObjectID, version int64) bson.M {
+	if version == 0 {
+		return bson.M{"_id": id, "version": bson.M{"$in": bson.A{0, nil}}}
+	}
+	return bson.M{"_id": id, "version": version}
+}
+
+// missingOrModified explains why a version-filtered write matched nothing.
+func (r *mongoOrderRepository) missingOrModified(ctx context.Context, id primitive.ObjectID) error {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"_id": id})
 	if err != nil {
 		return err
 	}
+	if count == 0 {
+		return domain.ErrOrderNotFound
+	}
+	return domain.ErrVersionMismatch
+}
+
+// MigrateLegacyOrders rewrites orders stored before Money and items into
+// the current form: float prices become Money subdocuments, taken to be in
+// money.LegacyCurrency since they predate currencies, and the top-level
+// product_id and quantity become the order's one item. It is safe to run on
+// every start.
+func MigrateLegacyOrders(ctx context.Context, collection *mongo.Collection) (int, error) {
+	cursor, err := collection.Find(ctx, bson.M{"$or": bson.A{
+		bson.M{"total_price": bson.M{"$type": "number"}},
+		bson.M{"product_id": bson.M{"$exists": true}},
+	}})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
 
-	if result.DeletedCount == 0 {
-		return mongo.ErrNoDocuments
+	migrated := 0
+	for cursor.Next(ctx) {
+		// domain.Order decodes both legacy forms itself, so the order only has
+		// to be read and written back.
+		var order domain.Order
+		if err := cursor.Decode(&order); err != nil {
+			return migrated, err
+		}
+		if _, err := collection.UpdateOne(ctx, bson.M{"_id": order.ID}, legacyOrderUpdate(&order)); err != nil {
+			return migrated, err
+		}
+		migrated++
 	}
+	return migrated, cursor.Err()
+}
 
-	return nil
+// legacyOrderUpdate writes back a legacy order as decoded and drops the
+// fields items replaced.
+func legacyOrderUpdate(order *domain.Order) bson.M {
+	return bson.M{
+		"$set":   bson.M{"items": order.Items, "total_price": order.TotalPrice},
+		"$unset": bson.M{"product_id": "", "quantity": ""},
+	}
 }
@@ -0,0 +1,21 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestVersionFilter(t *testing.T) {
+	id := primitive.NewObjectID()
+
+	t.Run("Versioned", func(t *testing.T) {
+		assert.Equal(t, bson.M{"_id": id, "version": int64(3)}, versionFilter(id, 3))
+	})
+
+	t.Run("Unversioned Documents", func(t *testing.T) {
+		assert.Equal(t, bson.M{"_id": id, "version": bson.M{"$in": bson.A{0, nil}}}, versionFilter(id, 0))
+	})
+}
@@ -0,0 +1,77 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"order-service/internal/domain"
+)
+
+// outboxMessage is the stored form of an event. PublishedAt stays nil until
+// the relay has handed the event to the publisher.
+type outboxMessage struct {
+	domain.OrderEvent `bson:",inline"`
+	PublishedAt       *time.Time `bson:"published_at"`
+}
+
+type mongoOutboxRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoOutboxRepository(collection *mongo.Collection) domain.OutboxRepository {
+	return &mongoOutboxRepository{
+		collection: collection,
+	}
+}
+
+// EnsureOutboxIndexes creates the index the relay uses to find pending events.
+func EnsureOutboxIndexes(ctx context.Context, collection *mongo.Collection) error {
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "published_at", Value: 1}, {Key: "_id", Value: 1}},
+	})
+	return err
+}
+
+func (r *mongoOutboxRepository) Add(ctx context.Context, events ...domain.OrderEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, len(events))
+	for i, event := range events {
+		docs[i] = outboxMessage{OrderEvent: event}
+	}
+	_, err := r.collection.InsertMany(ctx, docs)
+	return err
+}
+
+func (r *mongoOutboxRepository) Pending(ctx context.Context, limit int) ([]domain.OrderEvent, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, bson.M{"published_at": nil}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var messages []outboxMessage
+	if err := cursor.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+
+	events := make([]domain.OrderEvent, len(messages))
+	for i, message := range messages {
+		events[i] = message.OrderEvent
+	}
+	return events, nil
+}
+
+func (r *mongoOutboxRepository) MarkPublished(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"published_at": time.Now()}})
+	return err
+}
@@ -0,0 +1,55 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"order-service/internal/domain"
+)
+
+type mongoPaymentRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoPaymentRepository(collection *mongo.Collection) domain.PaymentRepository {
+	return &mongoPaymentRepository{
+		collection: collection,
+	}
+}
+
+// EnsurePaymentIndexes allows one payment per order.
+func EnsurePaymentIndexes(ctx context.Context, collection *mongo.Collection) error {
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "order_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+func (r *mongoPaymentRepository) Create(ctx context.Context, payment *domain.Payment) error {
+	if payment.ID.IsZero() {
+		payment.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, payment)
+	return err
+}
+
+func (r *mongoPaymentRepository) Update(ctx context.Context, payment *domain.Payment) error {
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": payment.ID}, payment)
+	return err
+}
+
+func (r *mongoPaymentRepository) GetByOrderID(ctx context.Context, orderID primitive.ObjectID) (*domain.Payment, error) {
+	var payment domain.Payment
+	err := r.collection.FindOne(ctx, bson.M{"order_id": orderID}).Decode(&payment)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
@@ -0,0 +1,96 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"order-service/internal/domain"
+)
+
+type mongoSagaRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoSagaRepository(collection *mongo.Collection) domain.SagaRepository {
+	return &mongoSagaRepository{
+		collection: collection,
+	}
+}
+
+// EnsureSagaIndexes allows one saga per order and indexes the lookup for
+// stalled sagas.
+func EnsureSagaIndexes(ctx context.Context, collection *mongo.Collection) error {
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "order_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "updated_at", Value: 1}},
+		},
+	})
+	return err
+}
+
+func (r *mongoSagaRepository) Create(ctx context.Context, saga *domain.CheckoutSaga) error {
+	if saga.ID.IsZero() {
+		saga.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, saga)
+	return err
+}
+
+func (r *mongoSagaRepository) Update(ctx context.Context, saga *domain.CheckoutSaga) error {
+	filter := bson.M{
+		"_id":              saga.ID,
+		"owner":            saga.Owner,
+		"lease_expires_at": bson.M{"$gt": time.Now()},
+	}
+	result, err := r.collection.ReplaceOne(ctx, filter, saga)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrSagaLeaseLost
+	}
+	return nil
+}
+
+func (r *mongoSagaRepository) GetByOrderID(ctx context.Context, orderID primitive.ObjectID) (*domain.CheckoutSaga, error) {
+	var saga domain.CheckoutSaga
+	err := r.collection.FindOne(ctx, bson.M{"order_id": orderID}).Decode(&saga)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &saga, nil
+}
+
+func (r *mongoSagaRepository) ClaimStalled(ctx context.Context, before time.Time, owner string, leaseUntil time.Time) (*domain.CheckoutSaga, error) {
+	// A missing lease counts as run out.
+	filter := bson.M{
+		"status":           bson.M{"$in": []domain.SagaStatus{domain.SagaRunning, domain.SagaCompensating}},
+		"updated_at":       bson.M{"$lt": before},
+		"lease_expires_at": bson.M{"$not": bson.M{"$gt": time.Now()}},
+	}
+	update := bson.M{"$set": bson.M{"owner": owner, "lease_expires_at": leaseUntil}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "updated_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var saga domain.CheckoutSaga
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&saga)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &saga, nil
+}
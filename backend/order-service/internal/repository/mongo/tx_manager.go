@@ -0,0 +1,34 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"order-service/internal/domain"
+)
+
+// mongoTxManager runs transactions on a client session. The session travels
+// in the context, so repositories join the transaction just by using it.
+// Transactions need MongoDB running as a replica set.
+type mongoTxManager struct {
+	client *mongo.Client
+}
+
+func NewMongoTxManager(client *mongo.Client) domain.TxManager {
+	return &mongoTxManager{
+		client: client,
+	}
+}
+
+func (m *mongoTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := m.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
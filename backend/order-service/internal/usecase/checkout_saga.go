@@ -0,0 +1,286 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"order-service/internal/domain"
+)
+
+const (
+	sagaBatchSize = 100
+	// sagaLease is how long a runner owns a saga it started or claimed, and
+	// so how long it gets to drive the saga before another may take over.
+	sagaLease = time.Minute
+)
+
+// CheckoutSaga orchestrates checkout: create the order, reserve its stock,
+// authorize payment and confirm it. Saga state is saved after every step;
+// when a step fails the completed steps are undone in reverse order (void
+// payment, release stock, mark the order failed).
+//
+// Every step and compensation is idempotent per order, so a saga resumed
+// after a crash may safely repeat the step it was in the middle of.
+type CheckoutSaga struct {
+	orderRepo domain.OrderRepository
+	sagas     domain.SagaRepository
+	inventory domain.Inventory
+	payments  domain.Payments
+	outbox    domain.OutboxRepository
+	tx        domain.TxManager
+	// owner identifies this runner in the leases it takes on sagas.
+	owner string
+}
+
+type sagaStep struct {
+	name       domain.SagaStep
+	execute    func(ctx context.Context, saga *domain.CheckoutSaga, order *domain.Order) error
+	compensate func(ctx context.Context, saga *domain.CheckoutSaga, order *domain.Order) error
+}
+
+func NewCheckoutSaga(orderRepo domain.OrderRepository, sagas domain.SagaRepository, inventory domain.Inventory, payments domain.Payments, outbox domain.OutboxRepository, tx domain.TxManager) *CheckoutSaga {
+	return &CheckoutSaga{
+		orderRepo: orderRepo,
+		sagas:     sagas,
+		inventory: inventory,
+		payments:  payments,
+		outbox:    outbox,
+		tx:        tx,
+		owner:     primitive.NewObjectID().Hex(),
+	}
+}
+
+// steps lists the saga in execution order. Creating the order has no execute
+// func because Start does it together with saving the saga.
+func (c *CheckoutSaga) steps() []sagaStep {
+	return []sagaStep{
+		{name: domain.SagaStepCreateOrder, compensate: c.failOrder},
+		{name: domain.SagaStepReserveStock, execute: c.reserveStock, compensate: c.releaseStock},
+		{name: domain.SagaStepAuthorizePayment, execute: c.authorizePayment, compensate: c.voidPayment},
+		{name: domain.SagaStepConfirmOrder, execute: c.confirmOrder},
+	}
+}
+
+// Start stores the pending order, its created event and a new saga in one
+// transaction, then runs the remaining steps. If a step fails, its error is
+// returned once the saga has been compensated as far as possible; the order
+// itself is kept, marked failed.
+//
+// Once stored, the saga runs to the end even if the caller goes away, for at
+// most sagaLease; after that Run picks it up.
+func (c *CheckoutSaga) Start(ctx context.Context, order *domain.Order) error {
+	now := time.Now()
+	saga := &domain.CheckoutSaga{
+		OrderID:        order.ID,
+		Status:         domain.SagaRunning,
+		Completed:      []domain.SagaStep{domain.SagaStepCreateOrder},
+		Owner:          c.owner,
+		LeaseExpiresAt: now.Add(sagaLease),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	err := c.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := c.orderRepo.Create(ctx, order); err != nil {
+			return err
+		}
+		if err := c.sagas.Create(ctx, saga); err != nil {
+			return err
+		}
+		return c.outbox.Add(ctx, domain.NewOrderEvent(domain.EventOrderCreated, order, ""))
+	})
+	if err != nil {
+		return err
+	}
+
+	runCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sagaLease)
+	defer cancel()
+	err = c.run(runCtx, saga, order)
+	ordersCreated.WithLabelValues(string(order.Status)).Inc()
+	return err
+}
+
+// Run resumes stalled sagas every interval until ctx is cancelled. A saga is
+// stalled once it has not moved for stallAfter, which must comfortably exceed
+// how long a healthy checkout takes.
+func (c *CheckoutSaga) Run(ctx context.Context, interval, stallAfter time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := c.ResumeStalled(ctx, time.Now().Add(-stallAfter)); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "resume stalled checkouts", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ResumeStalled drives one batch of unfinished sagas last updated before the
+// given time to a final status, e.g. after the instance running them was
+// stopped. Each saga is claimed first, so runners on other instances never
+// work on the same one. It returns how many sagas it picked up.
+func (c *CheckoutSaga) ResumeStalled(ctx context.Context, before time.Time) (int, error) {
+	resumed := 0
+	for resumed < sagaBatchSize {
+		saga, err := c.sagas.ClaimStalled(ctx, before, c.owner, time.Now().Add(sagaLease))
+		if err != nil {
+			return resumed, err
+		}
+		if saga == nil {
+			break
+		}
+		resumed++
+
+		runCtx, cancel := context.WithTimeout(ctx, sagaLease)
+		switch err := c.resume(runCtx, saga); {
+		case errors.Is(err, domain.ErrSagaLeaseLost):
+			slog.WarnContext(ctx, "checkout saga taken over by another runner", "order_id", saga.OrderID.Hex())
+		case err != nil:
+			slog.ErrorContext(ctx, "checkout saga", "order_id", saga.OrderID.Hex(), "error", err)
+		}
+		cancel()
+	}
+	return resumed, nil
+}
+
+func (c *CheckoutSaga) resume(ctx context.Context, saga *domain.CheckoutSaga) error {
+	order, err := c.orderRepo.GetByID(ctx, saga.OrderID)
+	if err != nil {
+		return err
+	}
+	if order == nil {
+		return domain.ErrOrderNotFound
+	}
+	if saga.Status == domain.SagaCompensating {
+		return c.compensate(ctx, saga, order)
+	}
+	return c.run(ctx, saga, order)
+}
+
+func (c *CheckoutSaga) run(ctx context.Context, saga *domain.CheckoutSaga, order *domain.Order) error {
+	for _, step := range c.steps() {
+		if saga.HasCompleted(step.name) {
+			continue
+		}
+		if err := step.execute(ctx, saga, order); err != nil {
+			saga.Status = domain.SagaCompensating
+			saga.Failure = fmt.Sprintf("%s: %v", step.name, err)
+			if err := c.compensate(ctx, saga, order); errors.Is(err, domain.ErrSagaLeaseLost) {
+				return err
+			} else if err != nil {
+				// The saga stays compensating and is retried by Run.
+				slog.ErrorContext(ctx, "checkout saga compensation", "order_id", order.ID.Hex(), "error", err)
+			}
+			return err
+		}
+		saga.Completed = append(saga.Completed, step.name)
+		if err := c.save(ctx, saga); err != nil {
+			return err
+		}
+	}
+
+	saga.Status = domain.SagaCompleted
+	return c.save(ctx, saga)
+}
+
+// compensate undoes the completed steps, newest first, and marks the saga
+// failed. It stops at the first compensation that fails.
+func (c *CheckoutSaga) compensate(ctx context.Context, saga *domain.CheckoutSaga, order *domain.Order) error {
+	if err := c.save(ctx, saga); err != nil {
+		return err
+	}
+
+	steps := c.steps()
+	for i := len(steps) - 1; i >= 0; i-- {
+		step := steps[i]
+		if !saga.HasCompleted(step.name) || saga.HasCompensated(step.name) || step.compensate == nil {
+			continue
+		}
+		if err := step.compensate(ctx, saga, order); err != nil {
+			return fmt.Errorf("compensate %s: %w", step.name, err)
+		}
+		saga.Compensated = append(saga.Compensated, step.name)
+		if err := c.save(ctx, saga); err != nil {
+			return err
+		}
+	}
+
+	saga.Status = domain.SagaFailed
+	return c.save(ctx, saga)
+}
+
+// save stores the saga's progress. It fails with domain.ErrSagaLeaseLost once
+// another runner has claimed the saga, and every caller then stops at once so
+// the two never drive the same checkout.
+func (c *CheckoutSaga) save(ctx context.Context, saga *domain.CheckoutSaga) error {
+	saga.UpdatedAt = time.Now()
+	return c.sagas.Update(ctx, saga)
+}
+
+func (c *CheckoutSaga) reserveStock(ctx context.Context, saga *domain.CheckoutSaga, order *domain.Order) error {
+	return c.inventory.Reserve(ctx, order.ID, order.Items)
+}
+
+func (c *CheckoutSaga) releaseStock(ctx context.Context, saga *domain.CheckoutSaga, order *domain.Order) error {
+	return c.inventory.Release(ctx, order.ID)
+}
+
+func (c *CheckoutSaga) authorizePayment(ctx context.Context, saga *domain.CheckoutSaga, order *domain.Order) error {
+	payment, err := c.payments.Authorize(ctx, order)
+	if err != nil {
+		return err
+	}
+	saga.PaymentID = payment.ID
+	return nil
+}
+
+func (c *CheckoutSaga) voidPayment(ctx context.Context, saga *domain.CheckoutSaga, order *domain.Order) error {
+	return c.payments.Void(ctx, order.ID)
+}
+
+func (c *CheckoutSaga) confirmOrder(ctx context.Context, saga *domain.CheckoutSaga, order *domain.Order) error {
+	if order.Status == domain.OrderStatusConfirmed {
+		return nil
+	}
+	return c.setStatus(ctx, order, domain.OrderStatusConfirmed)
+}
+
+// failOrder marks the order failed. An order that has already moved on, e.g.
+// cancelled by its owner, is left as it is.
+func (c *CheckoutSaga) failOrder(ctx context.Context, saga *domain.CheckoutSaga, order *domain.Order) error {
+	if order.Status != domain.OrderStatusPending {
+		return nil
+	}
+	return c.setStatus(ctx, order, domain.OrderStatusFailed)
+}
+
+func (c *CheckoutSaga) setStatus(ctx context.Context, order *domain.Order, status domain.OrderStatus) error {
+	if err := order.Status.TransitionTo(status); err != nil {
+		return err
+	}
+
+	previous := order.Status
+	order.Status = status
+	order.UpdatedAt = time.Now()
+	err := c.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := c.orderRepo.Update(ctx, order); err != nil {
+			return err
+		}
+		return c.outbox.Add(ctx, domain.StatusChangeEvents(order, previous)...)
+	})
+	if err != nil {
+		order.Status = previous
+		return err
+	}
+	order.Version++
+	recordStatusChange(previous, status)
+	return nil
+}
@@ -0,0 +1,270 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"order-service/internal/domain"
+	"order-service/internal/payment"
+	"order-service/internal/repository/memory"
+	mockRepo "order-service/internal/repository/mock"
+)
+
+func paymentStatus(payments *payment.Service, orderID primitive.ObjectID) domain.PaymentStatus {
+	p, err := payments.Get(context.Background(), orderID)
+	if err != nil {
+		return ""
+	}
+	return p.Status
+}
+
+func TestCheckoutSaga(t *testing.T) {
+	mockRepo := new(mockRepo.MockOrderRepository)
+	sagas := memory.NewSagaRepository()
+	inventory := memory.NewInventory()
+	inventory.SetStock("456", 3)
+	payments := newTestPayments()
+	checkout := NewCheckoutSaga(mockRepo, sagas, inventory, payments, memory.NewOutbox(), memory.TxManager{})
+
+	newOrder := func(quantity int) *domain.Order {
+		return &domain.Order{
+			ID:     primitive.NewObjectID(),
+			UserID: "123",
+			Items:  []domain.OrderItem{{ProductID: "456", Quantity: quantity}},
+			Status: domain.OrderStatusPending,
+		}
+	}
+	withStatus := func(status domain.OrderStatus) interface{} {
+		return mock.MatchedBy(func(o *domain.Order) bool { return o.Status == status })
+	}
+
+	t.Run("Completes", func(t *testing.T) {
+		order := newOrder(2)
+		mockRepo.On("Create", mock.Anything, order).Return(nil).Once()
+		mockRepo.On("Update", mock.Anything, withStatus(domain.OrderStatusConfirmed)).Return(nil).Once()
+
+		err := checkout.Start(context.Background(), order)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.OrderStatusConfirmed, order.Status)
+		assert.Equal(t, memory.ReservationReserved, inventory.ReservationStatus(order.ID))
+
+		saga, _ := sagas.GetByOrderID(context.Background(), order.ID)
+		assert.Equal(t, domain.SagaCompleted, saga.Status)
+		assert.Equal(t, []domain.SagaStep{
+			domain.SagaStepCreateOrder, domain.SagaStepReserveStock,
+			domain.SagaStepAuthorizePayment, domain.SagaStepConfirmOrder,
+		}, saga.Completed)
+		assert.Equal(t, domain.PaymentAuthorized, paymentStatus(payments, order.ID))
+		mockRepo.AssertExpectations(t)
+
+		inventory.Release(context.Background(), order.ID)
+	})
+
+	t.Run("Outlives The Caller", func(t *testing.T) {
+		order := newOrder(1)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		live := mock.MatchedBy(func(ctx context.Context) bool { return ctx.Err() == nil })
+		mockRepo.On("Create", mock.Anything, order).Run(func(mock.Arguments) { cancel() }).Return(nil).Once()
+		mockRepo.On("Update", live, withStatus(domain.OrderStatusConfirmed)).Return(nil).Once()
+
+		err := checkout.Start(ctx, order)
+
+		assert.NoError(t, err)
+		saga, _ := sagas.GetByOrderID(context.Background(), order.ID)
+		assert.Equal(t, domain.SagaCompleted, saga.Status)
+		mockRepo.AssertExpectations(t)
+
+		inventory.Release(context.Background(), order.ID)
+	})
+
+	t.Run("Insufficient Stock", func(t *testing.T) {
+		order := newOrder(4)
+		mockRepo.On("Create", mock.Anything, order).Return(nil).Once()
+		mockRepo.On("Update", mock.Anything, withStatus(domain.OrderStatusFailed)).Return(nil).Once()
+
+		err := checkout.Start(context.Background(), order)
+
+		assert.ErrorIs(t, err, domain.ErrInsufficientStock)
+		assert.Equal(t, domain.OrderStatusFailed, order.Status)
+		assert.Equal(t, 3, inventory.Available("456"))
+
+		saga, _ := sagas.GetByOrderID(context.Background(), order.ID)
+		assert.Equal(t, domain.SagaFailed, saga.Status)
+		assert.Empty(t, saga.PaymentID)
+		assert.Contains(t, saga.Failure, "reserve_stock")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Payment Declined", func(t *testing.T) {
+		order := newOrder(1)
+		order.TotalPrice = usd(1051)
+		mockRepo.On("Create", mock.Anything, order).Return(nil).Once()
+		mockRepo.On("Update", mock.Anything, withStatus(domain.OrderStatusFailed)).Return(nil).Once()
+
+		err := checkout.Start(context.Background(), order)
+
+		assert.ErrorIs(t, err, domain.ErrPaymentDeclined)
+		assert.Equal(t, domain.OrderStatusFailed, order.Status)
+		assert.Equal(t, memory.ReservationReleased, inventory.ReservationStatus(order.ID))
+		assert.Equal(t, 3, inventory.Available("456"))
+
+		saga, _ := sagas.GetByOrderID(context.Background(), order.ID)
+		assert.Equal(t, domain.SagaFailed, saga.Status)
+		assert.Equal(t, []domain.SagaStep{domain.SagaStepReserveStock, domain.SagaStepCreateOrder}, saga.Compensated)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Voids Payment When Confirmation Fails", func(t *testing.T) {
+		order := newOrder(1)
+		mockRepo.On("Create", mock.Anything, order).Return(nil).Once()
+		mockRepo.On("Update", mock.Anything, withStatus(domain.OrderStatusConfirmed)).Return(assert.AnError).Once()
+		mockRepo.On("Update", mock.Anything, withStatus(domain.OrderStatusFailed)).Return(nil).Once()
+
+		err := checkout.Start(context.Background(), order)
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, domain.OrderStatusFailed, order.Status)
+
+		saga, _ := sagas.GetByOrderID(context.Background(), order.ID)
+		assert.Equal(t, domain.SagaFailed, saga.Status)
+		assert.Equal(t, domain.PaymentVoided, paymentStatus(payments, order.ID))
+		assert.Equal(t, 3, inventory.Available("456"))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Nothing Held When Create Fails", func(t *testing.T) {
+		order := newOrder(1)
+		mockRepo.On("Create", mock.Anything, order).Return(assert.AnError).Once()
+
+		err := checkout.Start(context.Background(), order)
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Empty(t, inventory.ReservationStatus(order.ID))
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestResumeStalledSagas(t *testing.T) {
+	mockRepo := new(mockRepo.MockOrderRepository)
+	sagas := memory.NewSagaRepository()
+	inventory := newTestInventory()
+	payments := newTestPayments()
+	checkout := NewCheckoutSaga(mockRepo, sagas, inventory, payments, memory.NewOutbox(), memory.TxManager{})
+
+	stalledAt := time.Now().Add(-time.Hour)
+	items := []domain.OrderItem{{ProductID: "456", Quantity: 1}}
+
+	t.Run("Resumes Running Saga", func(t *testing.T) {
+		order := &domain.Order{ID: primitive.NewObjectID(), UserID: "123", Items: items, Status: domain.OrderStatusPending}
+		inventory.Reserve(context.Background(), order.ID, items)
+		sagas.Create(context.Background(), &domain.CheckoutSaga{
+			OrderID:   order.ID,
+			Status:    domain.SagaRunning,
+			Completed: []domain.SagaStep{domain.SagaStepCreateOrder, domain.SagaStepReserveStock},
+			UpdatedAt: stalledAt,
+		})
+		mockRepo.On("GetByID", mock.Anything, order.ID).Return(order, nil).Once()
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+
+		resumed, err := checkout.ResumeStalled(context.Background(), time.Now().Add(-time.Minute))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, resumed)
+		assert.Equal(t, domain.OrderStatusConfirmed, order.Status)
+
+		saga, _ := sagas.GetByOrderID(context.Background(), order.ID)
+		assert.Equal(t, domain.SagaCompleted, saga.Status)
+		assert.Equal(t, domain.PaymentAuthorized, paymentStatus(payments, order.ID))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Finishes Compensation", func(t *testing.T) {
+		order := &domain.Order{ID: primitive.NewObjectID(), UserID: "123", Items: items, Status: domain.OrderStatusPending}
+		inventory.Reserve(context.Background(), order.ID, items)
+		sagas.Create(context.Background(), &domain.CheckoutSaga{
+			OrderID:   order.ID,
+			Status:    domain.SagaCompensating,
+			Completed: []domain.SagaStep{domain.SagaStepCreateOrder, domain.SagaStepReserveStock},
+			UpdatedAt: stalledAt,
+		})
+		mockRepo.On("GetByID", mock.Anything, order.ID).Return(order, nil).Once()
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+
+		_, err := checkout.ResumeStalled(context.Background(), time.Now().Add(-time.Minute))
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.OrderStatusFailed, order.Status)
+		assert.Equal(t, memory.ReservationReleased, inventory.ReservationStatus(order.ID))
+
+		saga, _ := sagas.GetByOrderID(context.Background(), order.ID)
+		assert.Equal(t, domain.SagaFailed, saga.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Claims Each Saga Once", func(t *testing.T) {
+		order := &domain.Order{ID: primitive.NewObjectID(), UserID: "123", Items: items, Status: domain.OrderStatusPending}
+		sagas.Create(context.Background(), &domain.CheckoutSaga{
+			OrderID:   order.ID,
+			Status:    domain.SagaRunning,
+			Completed: []domain.SagaStep{domain.SagaStepCreateOrder},
+			UpdatedAt: stalledAt,
+		})
+		other := NewCheckoutSaga(mockRepo, sagas, inventory, payments, memory.NewOutbox(), memory.TxManager{})
+		// The first runner fails to load the order, so the saga stays
+		// unfinished but leased to it.
+		mockRepo.On("GetByID", mock.Anything, order.ID).Return(nil, assert.AnError).Once()
+
+		resumed, err := checkout.ResumeStalled(context.Background(), time.Now().Add(-time.Minute))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, resumed)
+
+		resumed, err = other.ResumeStalled(context.Background(), time.Now().Add(-time.Minute))
+		assert.NoError(t, err)
+		assert.Zero(t, resumed)
+
+		saga, _ := sagas.GetByOrderID(context.Background(), order.ID)
+		assert.Equal(t, checkout.owner, saga.Owner)
+		assert.True(t, saga.LeaseExpiresAt.After(time.Now()))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Stops Once Lease Is Lost", func(t *testing.T) {
+		order := &domain.Order{ID: primitive.NewObjectID(), UserID: "123", Items: items, Status: domain.OrderStatusPending}
+		stored := &domain.CheckoutSaga{
+			OrderID:        order.ID,
+			Status:         domain.SagaRunning,
+			Completed:      []domain.SagaStep{domain.SagaStepCreateOrder},
+			Owner:          "other-runner",
+			LeaseExpiresAt: time.Now().Add(time.Minute),
+			UpdatedAt:      stalledAt,
+		}
+		sagas.Create(context.Background(), stored)
+		// This runner still thinks it owns the saga.
+		stale := *stored
+		stale.Owner = checkout.owner
+
+		err := checkout.run(context.Background(), &stale, order)
+
+		assert.ErrorIs(t, err, domain.ErrSagaLeaseLost)
+		assert.Empty(t, paymentStatus(payments, order.ID))
+		saga, _ := sagas.GetByOrderID(context.Background(), order.ID)
+		assert.Equal(t, "other-runner", saga.Owner)
+		assert.Equal(t, []domain.SagaStep{domain.SagaStepCreateOrder}, saga.Completed)
+	})
+
+	t.Run("Skips Recent And Finished Sagas", func(t *testing.T) {
+		sagas.Create(context.Background(), &domain.CheckoutSaga{OrderID: primitive.NewObjectID(), Status: domain.SagaRunning, UpdatedAt: time.Now()})
+		sagas.Create(context.Background(), &domain.CheckoutSaga{OrderID: primitive.NewObjectID(), Status: domain.SagaCompleted, UpdatedAt: stalledAt})
+
+		resumed, err := checkout.ResumeStalled(context.Background(), time.Now().Add(-time.Minute))
+
+		assert.NoError(t, err)
+		assert.Zero(t, resumed)
+	})
+}
@@ -0,0 +1,25 @@
+package usecase
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"order-service/internal/domain"
+)
+
+var (
+	ordersCreated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "orders_created_total",
+		Help: "Orders created, by the status checkout left them in.",
+	}, []string{"status"})
+
+	orderStatusChanges = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "order_status_changes_total",
+		Help: "Order status changes, by previous and new status.",
+	}, []string{"from", "to"})
+)
+
+func recordStatusChange(from, to domain.OrderStatus) {
+	if from != to {
+		orderStatusChanges.WithLabelValues(string(from), string(to)).Inc()
+	}
+}
@@ -2,42 +2,521 @@ package usecase
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
 	"time"
 
-	"order-service/internal/domain"
+	"github.com/yourusername/ecommerce/pkg/money"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"order-service/internal/domain"
 )
 
 type orderUseCase struct {
 	orderRepo domain.OrderRepository
+	catalog   domain.ProductCatalog
+	inventory domain.Inventory
+	payments  domain.Payments
+	checkout  *CheckoutSaga
+	outbox    domain.OutboxRepository
+	tx        domain.TxManager
 }
 
-func NewOrderUseCase(orderRepo domain.OrderRepository) domain.OrderUseCase {
+// NewOrderUseCase wires the order usecase. New orders go through checkout;
+// every later write and the events it produces are stored through tx in one
+// transaction, keeping the order's stock reservation in inventory and its
+// payment in payments in step.
+func NewOrderUseCase(orderRepo domain.OrderRepository, catalog domain.ProductCatalog, inventory domain.Inventory, payments domain.Payments, checkout *CheckoutSaga, outbox domain.OutboxRepository, tx domain.TxManager) domain.OrderUseCase {
 	return &orderUseCase{
 		orderRepo: orderRepo,
+		catalog:   catalog,
+		inventory: inventory,
+		payments:  payments,
+		checkout:  checkout,
+		outbox:    outbox,
+		tx:        tx,
 	}
 }
 
 func (u *orderUseCase) CreateOrder(ctx context.Context, order *domain.Order) error {
+	principal, err := domain.PrincipalFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	// Only admins may place an order on someone else's behalf.
+	if !principal.IsAdmin() || order.UserID == "" {
+		order.UserID = principal.Subject
+	}
+
+	if err := u.priceItems(ctx, order.Items); err != nil {
+		return err
+	}
+	if err := order.CalculateTotals(); err != nil {
+		return err
+	}
+
 	order.CreatedAt = time.Now()
 	order.UpdatedAt = time.Now()
-	order.Status = "pending"
-	return u.orderRepo.Create(ctx, order)
+	order.Status = domain.OrderStatusPending
+	order.Version = 1
+
+	// The ID is chosen up front so every saga step can be keyed by it.
+	order.ID = primitive.NewObjectID()
+	return u.checkout.Start(ctx, order)
 }
 
-func (u *orderUseCase) GetOrder(ctx context.Context, id primitive.ObjectID) (*domain.Order, error) {
-	return u.orderRepo.GetByID(ctx, id)
+func (u *orderUseCase) GetOrder(ctx context.Context, id primitive.ObjectID, includeDeleted bool) (*domain.Order, error) {
+	principal, err := domain.PrincipalFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := u.orderRepo.GetByID(ctx, id)
+	if err != nil || order == nil {
+		return nil, err
+	}
+	if !principal.CanAccess(order.UserID) {
+		return nil, nil
+	}
+	if order.IsDeleted() && !(includeDeleted && principal.IsAdmin()) {
+		return nil, nil
+	}
+	return order, nil
 }
 
-func (u *orderUseCase) GetOrders(ctx context.Context, userID string) ([]domain.Order, error) {
-	return u.orderRepo.GetAll(ctx, userID)
+func (u *orderUseCase) GetOrders(ctx context.Context, filter domain.OrderFilter) (*domain.OrderPage, error) {
+	principal, err := domain.PrincipalFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !principal.IsAdmin() {
+		filter.UserID = principal.Subject
+		filter.IncludeDeleted = false
+	}
+
+	if filter.Sort == "" {
+		filter.Sort = domain.SortCreatedAtDesc
+	}
+	if !filter.Sort.IsValid() {
+		return nil, domain.ErrInvalidSort
+	}
+
+	switch {
+	case filter.Limit <= 0:
+		filter.Limit = domain.DefaultPageSize
+	case filter.Limit > domain.MaxPageSize:
+		filter.Limit = domain.MaxPageSize
+	}
+
+	return u.orderRepo.GetAll(ctx, filter)
 }
 
 func (u *orderUseCase) UpdateOrder(ctx context.Context, order *domain.Order) error {
+	existing, err := u.getAccessibleOrder(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	if err := existing.CheckVersion(order.Version); err != nil {
+		return err
+	}
+	order.UserID = existing.UserID
+	order.Version = existing.Version
+
+	var newItems []domain.OrderItem
+	switch {
+	case len(order.Items) == 0 || existing.ItemsLocked() && sameItems(existing.Items, order.Items):
+		order.Items = existing.Items
+	case existing.ItemsLocked():
+		return domain.ErrOrderItemsLocked
+	default:
+		if err := u.priceItems(ctx, order.Items); err != nil {
+			return err
+		}
+		newItems = order.Items
+	}
+	if err := order.CalculateTotals(); err != nil {
+		return err
+	}
+
+	if order.Status == "" {
+		order.Status = existing.Status
+	} else if order.Status != existing.Status {
+		if err := existing.Status.TransitionTo(order.Status); err != nil {
+			return err
+		}
+	}
+
+	if err := u.syncBeforeWrite(ctx, order.ID, existing.Items, newItems, order.TotalPrice, existing.Status, order.Status); err != nil {
+		return err
+	}
+
+	order.UpdatedAt = time.Now()
+	err = u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.orderRepo.Update(ctx, order); err != nil {
+			return err
+		}
+		return u.outbox.Add(ctx, domain.StatusChangeEvents(order, existing.Status)...)
+	})
+	if err != nil {
+		u.undoBeforeWrite(ctx, order.ID, existing.Items, newItems, existing.Status, order.Status)
+		return err
+	}
+	order.Version++
+	u.syncAfterWrite(ctx, order.ID, existing.Status, order.Status)
+	return nil
+}
+
+func (u *orderUseCase) PatchOrder(ctx context.Context, id primitive.ObjectID, patch domain.OrderPatch) (*domain.Order, error) {
+	existing, err := u.getAccessibleOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := existing.CheckVersion(patch.Version); err != nil {
+		return nil, err
+	}
+	patch.Version = existing.Version
+
+	if patch.Status != nil && *patch.Status != existing.Status {
+		if err := existing.Status.TransitionTo(*patch.Status); err != nil {
+			return nil, err
+		}
+	}
+
+	if patch.Items != nil && existing.ItemsLocked() {
+		if !sameItems(existing.Items, patch.Items) {
+			return nil, domain.ErrOrderItemsLocked
+		}
+		patch.Items = nil
+	}
+	if patch.Items != nil {
+		if err := u.priceItems(ctx, patch.Items); err != nil {
+			return nil, err
+		}
+		priced := domain.Order{Items: patch.Items}
+		if err := priced.CalculateTotals(); err != nil {
+			return nil, err
+		}
+		patch.TotalPrice = &priced.TotalPrice
+	} else {
+		patch.TotalPrice = nil
+	}
+
+	if patch.IsEmpty() {
+		return existing, nil
+	}
+
+	status, total := existing.Status, existing.TotalPrice
+	if patch.Status != nil {
+		status = *patch.Status
+	}
+	if patch.TotalPrice != nil {
+		total = *patch.TotalPrice
+	}
+	if err := u.syncBeforeWrite(ctx, id, existing.Items, patch.Items, total, existing.Status, status); err != nil {
+		return nil, err
+	}
+
+	patch.UpdatedAt = time.Now()
+	var order *domain.Order
+	err = u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		patched, err := u.orderRepo.Patch(ctx, id, patch)
+		if err != nil {
+			return err
+		}
+		order = patched
+		return u.outbox.Add(ctx, domain.StatusChangeEvents(order, existing.Status)...)
+	})
+	if err != nil {
+		u.undoBeforeWrite(ctx, id, existing.Items, patch.Items, existing.Status, status)
+		return nil, err
+	}
+	u.syncAfterWrite(ctx, id, existing.Status, order.Status)
+	return order, nil
+}
+
+func (u *orderUseCase) TransitionOrder(ctx context.Context, id primitive.ObjectID, status domain.OrderStatus) (*domain.Order, error) {
+	order, err := u.getAccessibleOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := order.Status.TransitionTo(status); err != nil {
+		return nil, err
+	}
+
+	previous := order.Status
+	if err := u.syncBeforeWrite(ctx, id, nil, nil, order.TotalPrice, previous, status); err != nil {
+		return nil, err
+	}
+	order.Status = status
+	order.UpdatedAt = time.Now()
+	err = u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.orderRepo.Update(ctx, order); err != nil {
+			return err
+		}
+		return u.outbox.Add(ctx, domain.StatusChangeEvents(order, previous)...)
+	})
+	if err != nil {
+		u.undoBeforeWrite(ctx, id, nil, nil, previous, status)
+		return nil, err
+	}
+	order.Version++
+	u.syncAfterWrite(ctx, id, previous, status)
+	return order, nil
+}
+
+// DeleteOrder soft-deletes the order, recording when and by whom. An order
+// deleted before it was paid is cancelled with it, giving back its stock and
+// payment holds.
+func (u *orderUseCase) DeleteOrder(ctx context.Context, id primitive.ObjectID, version int64) error {
+	existing, err := u.getAccessibleOrder(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := existing.CheckVersion(version); err != nil {
+		return err
+	}
+
+	principal, err := domain.PrincipalFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	previous := existing.Status
+	if isUnpaid(previous) {
+		existing.Status = domain.OrderStatusCancelled
+	}
+	now := time.Now()
+	existing.DeletedAt = &now
+	existing.DeletedBy = principal.Subject
+	existing.UpdatedAt = now
+	err = u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.orderRepo.Delete(ctx, existing); err != nil {
+			return err
+		}
+		events := append(domain.StatusChangeEvents(existing, previous), domain.NewOrderEvent(domain.EventOrderDeleted, existing, ""))
+		return u.outbox.Add(ctx, events...)
+	})
+	if err != nil {
+		return err
+	}
+	existing.Version++
+	u.syncAfterWrite(ctx, id, previous, existing.Status)
+	return nil
+}
+
+// RestoreOrder undoes a soft delete. Only admins can see deleted orders, so
+// only admins can restore them. An order deleted while unpaid no longer has
+// its stock and payment holds, so it comes back cancelled.
+func (u *orderUseCase) RestoreOrder(ctx context.Context, id primitive.ObjectID, version int64) (*domain.Order, error) {
+	principal, err := domain.PrincipalFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !principal.IsAdmin() {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	order, err := u.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, domain.ErrOrderNotFound
+	}
+	if !order.IsDeleted() {
+		return nil, domain.ErrOrderNotDeleted
+	}
+	if err := order.CheckVersion(version); err != nil {
+		return nil, err
+	}
+
+	// Orders deleted before deleting cancelled them are still unpaid here.
+	previous := order.Status
+	if isUnpaid(previous) {
+		order.Status = domain.OrderStatusCancelled
+	}
+	order.DeletedAt = nil
+	order.DeletedBy = ""
 	order.UpdatedAt = time.Now()
-	return u.orderRepo.Update(ctx, order)
+	err = u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.orderRepo.Restore(ctx, order); err != nil {
+			return err
+		}
+		events := append(domain.StatusChangeEvents(order, previous), domain.NewOrderEvent(domain.EventOrderRestored, order, ""))
+		return u.outbox.Add(ctx, events...)
+	})
+	if err != nil {
+		order.Status = previous
+		return nil, err
+	}
+	order.Version++
+	recordStatusChange(previous, order.Status)
+	return order, nil
+}
+
+// isUnpaid reports whether an order in status still holds stock and a payment
+// authorization that cancelling it would give back.
+func isUnpaid(status domain.OrderStatus) bool {
+	return status == domain.OrderStatusPending || status == domain.OrderStatusConfirmed
+}
+
+// getAccessibleOrder loads a live order for the caller in ctx. Orders owned by
+// someone else, and soft-deleted orders, are reported as not found so their
+// existence is not leaked.
+func (u *orderUseCase) getAccessibleOrder(ctx context.Context, id primitive.ObjectID) (*domain.Order, error) {
+	principal, err := domain.PrincipalFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := u.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil || !principal.CanAccess(order.UserID) || order.IsDeleted() {
+		return nil, domain.ErrOrderNotFound
+	}
+	return order, nil
+}
+
+// syncBeforeWrite brings the order's stock and payment in line with a write
+// that is about to happen: new items are reserved in place of the previous
+// ones, a move to paid captures the payment for total and commits the
+// reservation, and a move to refunded refunds it. Every call is idempotent, so a retried write
+// does not hold stock or move money twice. If the write fails,
+// undoBeforeWrite reverses it.
+func (u *orderUseCase) syncBeforeWrite(ctx context.Context, id primitive.ObjectID, previous, items []domain.OrderItem, total money.Money, from, to domain.OrderStatus) error {
+	reserve := len(items) > 0 && to != domain.OrderStatusCancelled
+	if reserve {
+		if err := u.inventory.Reserve(ctx, id, items); err != nil {
+			return err
+		}
+	}
+	if to == from {
+		return nil
+	}
+
+	var err error
+	switch to {
+	case domain.OrderStatusPaid:
+		err = u.pay(ctx, id, total)
+	case domain.OrderStatusRefunded:
+		_, err = u.payments.Refund(ctx, id)
+	}
+	if err != nil && reserve {
+		u.restoreItems(context.WithoutCancel(ctx), id, previous)
+	}
+	return err
 }
 
-func (u *orderUseCase) DeleteOrder(ctx context.Context, id primitive.ObjectID) error {
-	return u.orderRepo.Delete(ctx, id)
+// pay captures the order's payment, then commits its stock, so a capture
+// that is declined, or authorized for another total, leaves the stock
+// reserved rather than sold. If the commit fails, the capture is refunded.
+func (u *orderUseCase) pay(ctx context.Context, id primitive.ObjectID, total money.Money) error {
+	if _, err := u.payments.Capture(ctx, id, total); err != nil {
+		return err
+	}
+	if err := u.inventory.Commit(ctx, id); err != nil {
+		u.refund(context.WithoutCancel(ctx), id)
+		return err
+	}
+	return nil
+}
+
+// undoBeforeWrite puts back what syncBeforeWrite changed when the write it
+// prepared for then failed, leaving the order as it was: a payment taken for
+// it is refunded and its stock reopened, and its previous items are reserved
+// again in place of the rejected ones. It is best effort, like releaseHolds,
+// and runs even if ctx was cancelled, since that may be why the write failed.
+func (u *orderUseCase) undoBeforeWrite(ctx context.Context, id primitive.ObjectID, previous, items []domain.OrderItem, from, to domain.OrderStatus) {
+	ctx = context.WithoutCancel(ctx)
+	if to == domain.OrderStatusPaid && from != domain.OrderStatusPaid {
+		if err := u.inventory.Reopen(ctx, id); err != nil {
+			slog.ErrorContext(ctx, "reopen stock", "order_id", id.Hex(), "error", err)
+		}
+		u.refund(ctx, id)
+	}
+	if len(items) > 0 && to != domain.OrderStatusCancelled {
+		u.restoreItems(ctx, id, previous)
+	}
+}
+
+// refund returns a payment taken for a write that did not happen. A failure
+// leaves the customer charged for an unpaid order, so it is logged as an
+// error for an operator to settle.
+func (u *orderUseCase) refund(ctx context.Context, id primitive.ObjectID) {
+	if _, err := u.payments.Refund(ctx, id); err != nil {
+		slog.ErrorContext(ctx, "refund payment", "order_id", id.Hex(), "error", err)
+	}
+}
+
+// restoreItems reserves the order's previous items again after a change to
+// them was rejected.
+func (u *orderUseCase) restoreItems(ctx context.Context, id primitive.ObjectID, previous []domain.OrderItem) {
+	if len(previous) == 0 {
+		return
+	}
+	if err := u.inventory.Reserve(ctx, id, previous); err != nil {
+		slog.WarnContext(ctx, "restore stock reservation", "order_id", id.Hex(), "error", err)
+	}
+}
+
+func (u *orderUseCase) syncAfterWrite(ctx context.Context, id primitive.ObjectID, from, to domain.OrderStatus) {
+	recordStatusChange(from, to)
+	if to == domain.OrderStatusCancelled && from != domain.OrderStatusCancelled {
+		u.releaseHolds(ctx, id)
+	}
+}
+
+// releaseHolds gives back the stock reserved for an order and voids its
+// payment authorization. It is best effort: a reservation that cannot be
+// released here still expires on its own, and so does an authorization at
+// the payment provider.
+func (u *orderUseCase) releaseHolds(ctx context.Context, id primitive.ObjectID) {
+	if err := u.inventory.Release(ctx, id); err != nil {
+		slog.WarnContext(ctx, "release stock", "order_id", id.Hex(), "error", err)
+	}
+	if err := u.payments.Void(ctx, id); err != nil {
+		slog.WarnContext(ctx, "void payment", "order_id", id.Hex(), "error", err)
+	}
+}
+
+// priceItems looks every item up in the product catalog and overwrites its SKU
+// and unit price, so whatever the client sent for those fields is ignored.
+func (u *orderUseCase) priceItems(ctx context.Context, items []domain.OrderItem) error {
+	if len(items) == 0 {
+		return domain.ErrInvalidOrderItems
+	}
+
+	for i := range items {
+		item := &items[i]
+		if item.ProductID == "" || item.Quantity <= 0 {
+			return domain.ErrInvalidOrderItems
+		}
+
+		product, err := u.catalog.GetProduct(ctx, item.ProductID)
+		if err != nil {
+			return err
+		}
+		if !product.Active {
+			return fmt.Errorf("%w: %s", domain.ErrProductUnavailable, item.ProductID)
+		}
+
+		item.SKU = product.SKU
+		item.UnitPrice = product.Price
+	}
+	return nil
+}
+
+// sameItems reports whether b asks for the same products and quantities as
+// a, line by line.
+func sameItems(a, b []domain.OrderItem) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].ProductID != b[i].ProductID || a[i].Quantity != b[i].Quantity {
+			return false
+		}
+	}
+	return true
 }
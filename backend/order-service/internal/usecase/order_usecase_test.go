@@ -5,81 +5,224 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/ecommerce/pkg/money"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"order-service/internal/domain"
+	"order-service/internal/payment"
+	"order-service/internal/repository/memory"
 	mockRepo "order-service/internal/repository/mock"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+var (
+	userCtx  = domain.ContextWithPrincipal(context.Background(), &domain.Principal{Subject: "123"})
+	adminCtx = domain.ContextWithPrincipal(context.Background(), &domain.Principal{Subject: "admin-1", Roles: []string{domain.RoleAdmin}})
+)
+
+func usd(cents int64) money.Money {
+	return money.New(cents, "USD")
+}
+
+func newTestCatalog() *memory.ProductCatalog {
+	return memory.NewProductCatalog(
+		domain.CatalogProduct{ID: "456", SKU: "SKU-456", Name: "Keyboard", Price: usd(50000), Active: true},
+		domain.CatalogProduct{ID: "789", SKU: "SKU-789", Name: "Mouse", Price: usd(25000), Active: true},
+		domain.CatalogProduct{ID: "000", SKU: "SKU-000", Name: "Discontinued", Price: usd(10000), Active: false},
+		domain.CatalogProduct{ID: "321", SKU: "SKU-321", Name: "Imported Cable", Price: money.New(900, "EUR"), Active: true},
+	)
+}
+
+func newTestInventory() *memory.Inventory {
+	inventory := memory.NewInventory()
+	inventory.SetStock("456", 100)
+	inventory.SetStock("789", 100)
+	return inventory
+}
+
+// newTestPayments takes payments through the fake gateway, which approves
+// every amount the test catalog can produce.
+func newTestPayments() *payment.Service {
+	return payment.NewService(payment.NewFakeGateway(), memory.NewPaymentRepository())
+}
+
+func newTestUseCase(orderRepo domain.OrderRepository, inventory *memory.Inventory, payments *payment.Service, outbox *memory.Outbox) domain.OrderUseCase {
+	checkout := NewCheckoutSaga(orderRepo, memory.NewSagaRepository(), inventory, payments, outbox, memory.TxManager{})
+	return NewOrderUseCase(orderRepo, newTestCatalog(), inventory, payments, checkout, outbox, memory.TxManager{})
+}
+
+// retryingTx runs fn twice, dropping the first attempt as if the transaction
+// had hit a transient error and been retried.
+type retryingTx struct{}
+
+func (retryingTx) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	_ = fn(ctx)
+	return fn(ctx)
+}
+
 func TestCreateOrder(t *testing.T) {
 	mockRepo := new(mockRepo.MockOrderRepository)
-	useCase := NewOrderUseCase(mockRepo)
+	useCase := newTestUseCase(mockRepo, newTestInventory(), newTestPayments(), memory.NewOutbox())
 
 	t.Run("Success", func(t *testing.T) {
 		order := &domain.Order{
-			UserID:     "123",
-			ProductID:  "456",
-			Quantity:   2,
-			TotalPrice: 1000,
+			UserID: "123",
+			Items: []domain.OrderItem{
+				{ProductID: "456", UnitPrice: usd(1), Quantity: 2, LineTotal: usd(100)},
+				{ProductID: "789", SKU: "FAKE", Quantity: 1},
+			},
+			TotalPrice: usd(1),
 		}
 
 		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
 			return o.UserID == order.UserID &&
-				o.ProductID == order.ProductID &&
-				o.Quantity == order.Quantity &&
-				o.TotalPrice == order.TotalPrice &&
-				o.Status == "pending" &&
+				len(o.Items) == 2 &&
+				o.Items[0].SKU == "SKU-456" &&
+				o.Items[0].UnitPrice == usd(50000) &&
+				o.Items[0].LineTotal == usd(100000) &&
+				o.Items[1].SKU == "SKU-789" &&
+				o.Items[1].LineTotal == usd(25000) &&
+				o.TotalPrice == usd(125000) &&
+				o.Status == domain.OrderStatusPending &&
+				o.Version == 1 &&
 				!o.CreatedAt.IsZero() &&
 				!o.UpdatedAt.IsZero()
 		})).Return(nil).Once()
+		mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+			return o.Status == domain.OrderStatusConfirmed
+		})).Return(nil).Once()
+
+		created := testutil.ToFloat64(ordersCreated.WithLabelValues("confirmed"))
 
-		err := useCase.CreateOrder(context.Background(), order)
+		err := useCase.CreateOrder(userCtx, order)
 
 		assert.NoError(t, err)
-		assert.Equal(t, "pending", order.Status)
+		assert.Equal(t, domain.OrderStatusConfirmed, order.Status)
+		assert.Equal(t, created+1, testutil.ToFloat64(ordersCreated.WithLabelValues("confirmed")))
 		assert.NotZero(t, order.CreatedAt)
 		assert.NotZero(t, order.UpdatedAt)
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("Mixed Currencies", func(t *testing.T) {
+		err := useCase.CreateOrder(userCtx, &domain.Order{UserID: "123", Items: []domain.OrderItem{
+			{ProductID: "456", Quantity: 1},
+			{ProductID: "321", Quantity: 1},
+		}})
+
+		assert.ErrorIs(t, err, domain.ErrMixedCurrencies)
+	})
+
+	t.Run("Invalid Items", func(t *testing.T) {
+		for name, items := range map[string][]domain.OrderItem{
+			"Empty":             nil,
+			"Missing Product":   {{Quantity: 1, UnitPrice: usd(1000)}},
+			"Negative Quantity": {{ProductID: "456", Quantity: -1, UnitPrice: usd(1000)}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				err := useCase.CreateOrder(userCtx, &domain.Order{UserID: "123", Items: items})
+
+				assert.ErrorIs(t, err, domain.ErrInvalidOrderItems)
+			})
+		}
+	})
+
+	t.Run("Unknown Product", func(t *testing.T) {
+		order := &domain.Order{
+			UserID: "123",
+			Items:  []domain.OrderItem{{ProductID: "does-not-exist", Quantity: 1}},
+		}
+
+		err := useCase.CreateOrder(userCtx, order)
+
+		assert.ErrorIs(t, err, domain.ErrProductNotFound)
+	})
+
+	t.Run("Inactive Product", func(t *testing.T) {
+		order := &domain.Order{
+			UserID: "123",
+			Items:  []domain.OrderItem{{ProductID: "000", Quantity: 1}},
+		}
+
+		err := useCase.CreateOrder(userCtx, order)
+
+		assert.ErrorIs(t, err, domain.ErrProductUnavailable)
+	})
+
 	t.Run("Repository Error", func(t *testing.T) {
 		order := &domain.Order{
-			UserID:     "123",
-			ProductID:  "456",
-			Quantity:   2,
-			TotalPrice: 1000,
+			UserID: "123",
+			Items: []domain.OrderItem{
+				{ProductID: "456", SKU: "SKU-456", UnitPrice: usd(50000), Quantity: 2, LineTotal: usd(100000)},
+			},
+			TotalPrice: usd(100000),
 		}
 
 		mockRepo.On("Create", mock.Anything, mock.Anything).Return(assert.AnError).Once()
 
-		err := useCase.CreateOrder(context.Background(), order)
+		err := useCase.CreateOrder(userCtx, order)
 
 		assert.Error(t, err)
 		mockRepo.AssertExpectations(t)
 	})
+
+	t.Run("Owner Comes From Token", func(t *testing.T) {
+		order := &domain.Order{
+			UserID: "someone-else",
+			Items:  []domain.OrderItem{{ProductID: "456", Quantity: 1}},
+		}
+
+		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+			return o.UserID == "123"
+		})).Return(nil).Once()
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+
+		err := useCase.CreateOrder(userCtx, order)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Admin On Behalf Of User", func(t *testing.T) {
+		order := &domain.Order{
+			UserID: "someone-else",
+			Items:  []domain.OrderItem{{ProductID: "456", Quantity: 1}},
+		}
+
+		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+			return o.UserID == "someone-else"
+		})).Return(nil).Once()
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+
+		err := useCase.CreateOrder(adminCtx, order)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
 }
 
 func TestGetOrder(t *testing.T) {
 	mockRepo := new(mockRepo.MockOrderRepository)
-	useCase := NewOrderUseCase(mockRepo)
+	useCase := newTestUseCase(mockRepo, newTestInventory(), newTestPayments(), memory.NewOutbox())
 
 	t.Run("Success", func(t *testing.T) {
 		id := primitive.NewObjectID()
 		expectedOrder := &domain.Order{
-			ID:         id,
-			UserID:     "123",
-			ProductID:  "456",
-			Quantity:   2,
-			TotalPrice: 1000,
-			Status:     "pending",
+			ID:     id,
+			UserID: "123",
+			Items: []domain.OrderItem{
+				{ProductID: "456", SKU: "SKU-456", UnitPrice: usd(50000), Quantity: 2, LineTotal: usd(100000)},
+			},
+			TotalPrice: usd(100000),
+			Status:     domain.OrderStatusPending,
 			CreatedAt:  time.Now(),
 			UpdatedAt:  time.Now(),
 		}
 
 		mockRepo.On("GetByID", mock.Anything, id).Return(expectedOrder, nil).Once()
 
-		order, err := useCase.GetOrder(context.Background(), id)
+		order, err := useCase.GetOrder(userCtx, id, false)
 
 		assert.NoError(t, err)
 		assert.Equal(t, expectedOrder, order)
@@ -90,18 +233,61 @@ func TestGetOrder(t *testing.T) {
 		id := primitive.NewObjectID()
 		mockRepo.On("GetByID", mock.Anything, id).Return(nil, nil).Once()
 
-		order, err := useCase.GetOrder(context.Background(), id)
+		order, err := useCase.GetOrder(userCtx, id, false)
+
+		assert.NoError(t, err)
+		assert.Nil(t, order)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Other User's Order", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "999"}, nil).Once()
+
+		order, err := useCase.GetOrder(userCtx, id, false)
 
 		assert.NoError(t, err)
 		assert.Nil(t, order)
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("Admin Sees Any Order", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		expectedOrder := &domain.Order{ID: id, UserID: "999"}
+		mockRepo.On("GetByID", mock.Anything, id).Return(expectedOrder, nil).Once()
+
+		order, err := useCase.GetOrder(adminCtx, id, false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedOrder, order)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Deleted Order", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		deletedAt := time.Now()
+		deleted := &domain.Order{ID: id, UserID: "123", DeletedAt: &deletedAt}
+		mockRepo.On("GetByID", mock.Anything, id).Return(deleted, nil).Times(3)
+
+		order, err := useCase.GetOrder(userCtx, id, false)
+		assert.NoError(t, err)
+		assert.Nil(t, order)
+
+		order, err = useCase.GetOrder(userCtx, id, true)
+		assert.NoError(t, err)
+		assert.Nil(t, order, "include_deleted is ignored for non-admins")
+
+		order, err = useCase.GetOrder(adminCtx, id, true)
+		assert.NoError(t, err)
+		assert.Equal(t, deleted, order)
+		mockRepo.AssertExpectations(t)
+	})
+
 	t.Run("Repository Error", func(t *testing.T) {
 		id := primitive.NewObjectID()
 		mockRepo.On("GetByID", mock.Anything, id).Return(nil, assert.AnError).Once()
 
-		order, err := useCase.GetOrder(context.Background(), id)
+		order, err := useCase.GetOrder(userCtx, id, false)
 
 		assert.Error(t, err)
 		assert.Nil(t, order)
@@ -111,125 +297,937 @@ func TestGetOrder(t *testing.T) {
 
 func TestGetOrders(t *testing.T) {
 	mockRepo := new(mockRepo.MockOrderRepository)
-	useCase := NewOrderUseCase(mockRepo)
+	useCase := newTestUseCase(mockRepo, newTestInventory(), newTestPayments(), memory.NewOutbox())
 
 	t.Run("Success", func(t *testing.T) {
 		userID := "123"
-		expectedOrders := []domain.Order{
+		expectedPage := &domain.OrderPage{Orders: []domain.Order{
 			{
-				ID:         primitive.NewObjectID(),
-				UserID:     userID,
-				ProductID:  "456",
-				Quantity:   2,
-				TotalPrice: 1000,
-				Status:     "pending",
+				ID:     primitive.NewObjectID(),
+				UserID: userID,
+				Items: []domain.OrderItem{
+					{ProductID: "456", SKU: "SKU-456", UnitPrice: usd(50000), Quantity: 2, LineTotal: usd(100000)},
+				},
+				TotalPrice: usd(100000),
+				Status:     domain.OrderStatusPending,
 				CreatedAt:  time.Now(),
 				UpdatedAt:  time.Now(),
 			},
 			{
-				ID:         primitive.NewObjectID(),
-				UserID:     userID,
-				ProductID:  "789",
-				Quantity:   1,
-				TotalPrice: 500,
-				Status:     "completed",
+				ID:     primitive.NewObjectID(),
+				UserID: userID,
+				Items: []domain.OrderItem{
+					{ProductID: "789", SKU: "SKU-789", UnitPrice: usd(50000), Quantity: 1, LineTotal: usd(50000)},
+				},
+				TotalPrice: usd(50000),
+				Status:     domain.OrderStatusDelivered,
 				CreatedAt:  time.Now(),
 				UpdatedAt:  time.Now(),
 			},
-		}
+		}}
+
+		mockRepo.On("GetAll", mock.Anything, domain.OrderFilter{
+			UserID: userID,
+			Sort:   domain.SortCreatedAtDesc,
+			Limit:  domain.DefaultPageSize,
+		}).Return(expectedPage, nil).Once()
+
+		page, err := useCase.GetOrders(userCtx, domain.OrderFilter{UserID: userID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedPage, page)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Scoped To Caller", func(t *testing.T) {
+		mockRepo.On("GetAll", mock.Anything, mock.MatchedBy(func(f domain.OrderFilter) bool {
+			return f.UserID == "123" && !f.IncludeDeleted
+		})).Return(&domain.OrderPage{}, nil).Once()
+
+		_, err := useCase.GetOrders(userCtx, domain.OrderFilter{UserID: "999", IncludeDeleted: true})
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Admin Lists Any User", func(t *testing.T) {
+		mockRepo.On("GetAll", mock.Anything, mock.MatchedBy(func(f domain.OrderFilter) bool {
+			return f.UserID == "999" && f.IncludeDeleted
+		})).Return(&domain.OrderPage{}, nil).Once()
 
-		mockRepo.On("GetAll", mock.Anything, userID).Return(expectedOrders, nil).Once()
+		_, err := useCase.GetOrders(adminCtx, domain.OrderFilter{UserID: "999", IncludeDeleted: true})
 
-		orders, err := useCase.GetOrders(context.Background(), userID)
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Clamps Limit", func(t *testing.T) {
+		mockRepo.On("GetAll", mock.Anything, mock.MatchedBy(func(f domain.OrderFilter) bool {
+			return f.Limit == domain.MaxPageSize && f.Sort == domain.SortTotalPriceAsc
+		})).Return(&domain.OrderPage{}, nil).Once()
+
+		_, err := useCase.GetOrders(userCtx, domain.OrderFilter{Limit: 1000, Sort: domain.SortTotalPriceAsc})
 
 		assert.NoError(t, err)
-		assert.Equal(t, expectedOrders, orders)
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("Invalid Sort", func(t *testing.T) {
+		page, err := useCase.GetOrders(userCtx, domain.OrderFilter{Sort: "user_id"})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidSort)
+		assert.Nil(t, page)
+	})
+
 	t.Run("Repository Error", func(t *testing.T) {
-		userID := "123"
-		mockRepo.On("GetAll", mock.Anything, userID).Return(nil, assert.AnError).Once()
+		mockRepo.On("GetAll", mock.Anything, mock.Anything).Return(nil, assert.AnError).Once()
 
-		orders, err := useCase.GetOrders(context.Background(), userID)
+		page, err := useCase.GetOrders(userCtx, domain.OrderFilter{})
 
 		assert.Error(t, err)
-		assert.Nil(t, orders)
+		assert.Nil(t, page)
 		mockRepo.AssertExpectations(t)
 	})
 }
 
 func TestUpdateOrder(t *testing.T) {
 	mockRepo := new(mockRepo.MockOrderRepository)
-	useCase := NewOrderUseCase(mockRepo)
+	useCase := newTestUseCase(mockRepo, newTestInventory(), newTestPayments(), memory.NewOutbox())
 
 	t.Run("Success", func(t *testing.T) {
 		order := &domain.Order{
-			ID:         primitive.NewObjectID(),
-			UserID:     "123",
-			ProductID:  "456",
-			Quantity:   3,
-			TotalPrice: 1500,
-			Status:     "processing",
+			ID:     primitive.NewObjectID(),
+			UserID: "123",
+			Items: []domain.OrderItem{
+				{ProductID: "456", SKU: "SKU-456", UnitPrice: usd(50000), Quantity: 3, LineTotal: usd(150000)},
+			},
+			TotalPrice: usd(150000),
+			Status:     domain.OrderStatusConfirmed,
 		}
 
+		mockRepo.On("GetByID", mock.Anything, order.ID).Return(&domain.Order{ID: order.ID, UserID: "123", Status: domain.OrderStatusPending}, nil).Once()
 		mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
 			return o.ID == order.ID &&
 				o.UserID == order.UserID &&
-				o.ProductID == order.ProductID &&
-				o.Quantity == order.Quantity &&
+				len(o.Items) == len(order.Items) &&
 				o.TotalPrice == order.TotalPrice &&
 				o.Status == order.Status &&
 				!o.UpdatedAt.IsZero()
 		})).Return(nil).Once()
 
-		err := useCase.UpdateOrder(context.Background(), order)
+		err := useCase.UpdateOrder(userCtx, order)
 
 		assert.NoError(t, err)
 		assert.NotZero(t, order.UpdatedAt)
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("Writes Against Current Version", func(t *testing.T) {
+		order := &domain.Order{
+			ID:     primitive.NewObjectID(),
+			Items:  []domain.OrderItem{{ProductID: "456", Quantity: 1}},
+			Status: domain.OrderStatusPending,
+		}
+
+		mockRepo.On("GetByID", mock.Anything, order.ID).Return(&domain.Order{ID: order.ID, UserID: "123", Status: domain.OrderStatusPending, Version: 4}, nil).Once()
+		mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+			return o.Version == 4
+		})).Return(nil).Once()
+
+		err := useCase.UpdateOrder(userCtx, order)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), order.Version)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Retried Transaction Writes Against Same Version", func(t *testing.T) {
+		order := &domain.Order{
+			ID:     primitive.NewObjectID(),
+			Items:  []domain.OrderItem{{ProductID: "456", Quantity: 1}},
+			Status: domain.OrderStatusPending,
+		}
+		retrying := NewOrderUseCase(mockRepo, newTestCatalog(), newTestInventory(), newTestPayments(), nil, memory.NewOutbox(), retryingTx{})
+
+		mockRepo.On("GetByID", mock.Anything, order.ID).Return(&domain.Order{ID: order.ID, UserID: "123", Status: domain.OrderStatusPending, Version: 4}, nil).Once()
+		mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+			return o.Version == 4
+		})).Return(nil).Twice()
+
+		err := retrying.UpdateOrder(userCtx, order)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), order.Version)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Stale If-Match Version", func(t *testing.T) {
+		order := &domain.Order{
+			ID:      primitive.NewObjectID(),
+			Items:   []domain.OrderItem{{ProductID: "456", Quantity: 1}},
+			Status:  domain.OrderStatusPending,
+			Version: 3,
+		}
+
+		mockRepo.On("GetByID", mock.Anything, order.ID).Return(&domain.Order{ID: order.ID, UserID: "123", Status: domain.OrderStatusPending, Version: 4}, nil).Once()
+
+		err := useCase.UpdateOrder(userCtx, order)
+
+		assert.ErrorIs(t, err, domain.ErrVersionMismatch)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Concurrent Write", func(t *testing.T) {
+		order := &domain.Order{
+			ID:     primitive.NewObjectID(),
+			Items:  []domain.OrderItem{{ProductID: "456", Quantity: 1}},
+			Status: domain.OrderStatusPending,
+		}
+
+		mockRepo.On("GetByID", mock.Anything, order.ID).Return(&domain.Order{ID: order.ID, UserID: "123", Status: domain.OrderStatusPending, Version: 4}, nil).Once()
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(domain.ErrVersionMismatch).Once()
+
+		err := useCase.UpdateOrder(userCtx, order)
+
+		assert.ErrorIs(t, err, domain.ErrVersionMismatch)
+		assert.Equal(t, int64(4), order.Version)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Keeps Status And Items When Omitted", func(t *testing.T) {
+		order := &domain.Order{ID: primitive.NewObjectID()}
+		existing := &domain.Order{
+			ID:     order.ID,
+			UserID: "123",
+			Items:  []domain.OrderItem{{ProductID: "456", UnitPrice: usd(50000), Quantity: 2}},
+			Status: domain.OrderStatusPaid,
+		}
+
+		mockRepo.On("GetByID", mock.Anything, order.ID).Return(existing, nil).Once()
+		mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+			return o.ID == order.ID &&
+				o.Status == domain.OrderStatusPaid &&
+				len(o.Items) == 1 &&
+				o.TotalPrice == usd(100000)
+		})).Return(nil).Once()
+
+		err := useCase.UpdateOrder(userCtx, order)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Transition", func(t *testing.T) {
+		order := &domain.Order{
+			ID:     primitive.NewObjectID(),
+			Items:  []domain.OrderItem{{ProductID: "456", UnitPrice: usd(50000), Quantity: 2}},
+			Status: domain.OrderStatusDelivered,
+		}
+
+		mockRepo.On("GetByID", mock.Anything, order.ID).Return(&domain.Order{ID: order.ID, UserID: "123", Status: domain.OrderStatusPending}, nil).Once()
+
+		err := useCase.UpdateOrder(userCtx, order)
+
+		var transitionErr *domain.ErrInvalidTransition
+		assert.ErrorAs(t, err, &transitionErr)
+		assert.Equal(t, domain.OrderStatusPending, transitionErr.From)
+		assert.Equal(t, domain.OrderStatusDelivered, transitionErr.To)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		order := &domain.Order{ID: primitive.NewObjectID()}
+
+		mockRepo.On("GetByID", mock.Anything, order.ID).Return(nil, nil).Once()
+
+		err := useCase.UpdateOrder(userCtx, order)
+
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+		mockRepo.AssertExpectations(t)
+	})
+
 	t.Run("Repository Error", func(t *testing.T) {
 		order := &domain.Order{
-			ID:         primitive.NewObjectID(),
-			UserID:     "123",
-			ProductID:  "456",
-			Quantity:   3,
-			TotalPrice: 1500,
-			Status:     "processing",
+			ID:     primitive.NewObjectID(),
+			UserID: "123",
+			Items: []domain.OrderItem{
+				{ProductID: "456", SKU: "SKU-456", UnitPrice: usd(50000), Quantity: 3, LineTotal: usd(150000)},
+			},
+			TotalPrice: usd(150000),
+			Status:     domain.OrderStatusConfirmed,
 		}
 
+		mockRepo.On("GetByID", mock.Anything, order.ID).Return(&domain.Order{ID: order.ID, UserID: "123", Status: domain.OrderStatusPending}, nil).Once()
 		mockRepo.On("Update", mock.Anything, mock.Anything).Return(assert.AnError).Once()
 
-		err := useCase.UpdateOrder(context.Background(), order)
+		err := useCase.UpdateOrder(userCtx, order)
 
 		assert.Error(t, err)
 		mockRepo.AssertExpectations(t)
 	})
 }
 
-func TestDeleteOrder(t *testing.T) {
+func TestPatchOrder(t *testing.T) {
+	mockRepo := new(mockRepo.MockOrderRepository)
+	useCase := newTestUseCase(mockRepo, newTestInventory(), newTestPayments(), memory.NewOutbox())
+
+	t.Run("Status Only", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		status := domain.OrderStatusConfirmed
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123", Status: domain.OrderStatusPending}, nil).Once()
+		mockRepo.On("Patch", mock.Anything, id, mock.MatchedBy(func(p domain.OrderPatch) bool {
+			return p.Items == nil && p.TotalPrice == nil && *p.Status == status && !p.UpdatedAt.IsZero()
+		})).Return(&domain.Order{ID: id, UserID: "123", Status: status}, nil).Once()
+
+		order, err := useCase.PatchOrder(userCtx, id, domain.OrderPatch{Status: &status})
+
+		assert.NoError(t, err)
+		assert.Equal(t, status, order.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Items Are Repriced", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123", Status: domain.OrderStatusPending}, nil).Once()
+		mockRepo.On("Patch", mock.Anything, id, mock.MatchedBy(func(p domain.OrderPatch) bool {
+			return p.Status == nil &&
+				len(p.Items) == 2 &&
+				p.Items[0].SKU == "SKU-456" &&
+				p.Items[1].LineTotal == usd(50000) &&
+				p.TotalPrice != nil && *p.TotalPrice == usd(100000)
+		})).Return(&domain.Order{ID: id, UserID: "123", TotalPrice: usd(100000)}, nil).Once()
+
+		order, err := useCase.PatchOrder(userCtx, id, domain.OrderPatch{Items: []domain.OrderItem{
+			{ProductID: "456", UnitPrice: usd(100), Quantity: 1},
+			{ProductID: "789", Quantity: 2},
+		}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, usd(100000), order.TotalPrice)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Stale If-Match Version", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123", Status: domain.OrderStatusPending, Version: 2}, nil).Once()
+
+		_, err := useCase.PatchOrder(userCtx, id, domain.OrderPatch{Version: 1})
+
+		assert.ErrorIs(t, err, domain.ErrVersionMismatch)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Empty Patch", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		existing := &domain.Order{ID: id, UserID: "123", Status: domain.OrderStatusPending}
+		mockRepo.On("GetByID", mock.Anything, id).Return(existing, nil).Once()
+
+		order, err := useCase.PatchOrder(userCtx, id, domain.OrderPatch{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, existing, order)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Transition", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		status := domain.OrderStatusDelivered
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123", Status: domain.OrderStatusPending}, nil).Once()
+
+		order, err := useCase.PatchOrder(userCtx, id, domain.OrderPatch{Status: &status})
+
+		var transitionErr *domain.ErrInvalidTransition
+		assert.ErrorAs(t, err, &transitionErr)
+		assert.Nil(t, order)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Product", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123", Status: domain.OrderStatusPending}, nil).Once()
+
+		_, err := useCase.PatchOrder(userCtx, id, domain.OrderPatch{Items: []domain.OrderItem{{ProductID: "999", Quantity: 1}}})
+
+		assert.ErrorIs(t, err, domain.ErrProductNotFound)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockRepo.On("GetByID", mock.Anything, id).Return(nil, nil).Once()
+
+		_, err := useCase.PatchOrder(userCtx, id, domain.OrderPatch{})
+
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestTransitionOrder(t *testing.T) {
 	mockRepo := new(mockRepo.MockOrderRepository)
-	useCase := NewOrderUseCase(mockRepo)
+	useCase := newTestUseCase(mockRepo, newTestInventory(), newTestPayments(), memory.NewOutbox())
 
 	t.Run("Success", func(t *testing.T) {
 		id := primitive.NewObjectID()
-		mockRepo.On("Delete", mock.Anything, id).Return(nil).Once()
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123", Status: domain.OrderStatusPending}, nil).Once()
+		mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+			return o.ID == id && o.Status == domain.OrderStatusConfirmed && !o.UpdatedAt.IsZero()
+		})).Return(nil).Once()
+
+		order, err := useCase.TransitionOrder(userCtx, id, domain.OrderStatusConfirmed)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.OrderStatusConfirmed, order.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Transition", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123", Status: domain.OrderStatusCancelled}, nil).Once()
+
+		order, err := useCase.TransitionOrder(userCtx, id, domain.OrderStatusPending)
+
+		var transitionErr *domain.ErrInvalidTransition
+		assert.ErrorAs(t, err, &transitionErr)
+		assert.Nil(t, order)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Status", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123", Status: domain.OrderStatusPending}, nil).Once()
+
+		_, err := useCase.TransitionOrder(userCtx, id, domain.OrderStatus("processing"))
+
+		var transitionErr *domain.ErrInvalidTransition
+		assert.ErrorAs(t, err, &transitionErr)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockRepo.On("GetByID", mock.Anything, id).Return(nil, nil).Once()
 
-		err := useCase.DeleteOrder(context.Background(), id)
+		order, err := useCase.TransitionOrder(userCtx, id, domain.OrderStatusConfirmed)
+
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+		assert.Nil(t, order)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestDeleteOrder(t *testing.T) {
+	mockRepo := new(mockRepo.MockOrderRepository)
+	useCase := newTestUseCase(mockRepo, newTestInventory(), newTestPayments(), memory.NewOutbox())
+
+	t.Run("Soft Deletes", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123", Version: 2}, nil).Once()
+		mockRepo.On("Delete", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+			return o.ID == id &&
+				o.Version == 2 &&
+				o.DeletedAt != nil &&
+				o.DeletedBy == "123"
+		})).Return(nil).Once()
+
+		err := useCase.DeleteOrder(userCtx, id, 0)
 
 		assert.NoError(t, err)
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("Cancels Unpaid Order", func(t *testing.T) {
+		inventory, payments, outbox := newTestInventory(), newTestPayments(), memory.NewOutbox()
+		useCase := newTestUseCase(mockRepo, inventory, payments, outbox)
+		id := primitive.NewObjectID()
+		items := []domain.OrderItem{{ProductID: "456", Quantity: 1}}
+		inventory.Reserve(context.Background(), id, items)
+		payments.Authorize(context.Background(), &domain.Order{ID: id, TotalPrice: usd(50000)})
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123", Items: items, Status: domain.OrderStatusConfirmed, Version: 2}, nil).Once()
+		mockRepo.On("Delete", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+			return o.ID == id && o.Status == domain.OrderStatusCancelled && o.DeletedAt != nil
+		})).Return(nil).Once()
+
+		err := useCase.DeleteOrder(userCtx, id, 0)
+
+		assert.NoError(t, err)
+		events := outbox.Events()
+		assert.Len(t, events, 3)
+		assert.Equal(t, domain.EventOrderStatusChanged, events[0].Type)
+		assert.Equal(t, domain.EventOrderCancelled, events[1].Type)
+		assert.Equal(t, domain.EventOrderDeleted, events[2].Type)
+		assert.Equal(t, memory.ReservationReleased, inventory.ReservationStatus(id))
+		assert.Equal(t, domain.PaymentVoided, paymentStatus(payments, id))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Keeps Holds When Write Fails", func(t *testing.T) {
+		inventory, payments, outbox := newTestInventory(), newTestPayments(), memory.NewOutbox()
+		useCase := newTestUseCase(mockRepo, inventory, payments, outbox)
+		id := primitive.NewObjectID()
+		items := []domain.OrderItem{{ProductID: "456", Quantity: 1}}
+		inventory.Reserve(context.Background(), id, items)
+		payments.Authorize(context.Background(), &domain.Order{ID: id, TotalPrice: usd(50000)})
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123", Items: items, Status: domain.OrderStatusConfirmed, Version: 2}, nil).Once()
+		mockRepo.On("Delete", mock.Anything, mock.Anything).Return(domain.ErrVersionMismatch).Once()
+
+		err := useCase.DeleteOrder(userCtx, id, 0)
+
+		assert.ErrorIs(t, err, domain.ErrVersionMismatch)
+		assert.Empty(t, outbox.Events())
+		assert.Equal(t, memory.ReservationReserved, inventory.ReservationStatus(id))
+		assert.Equal(t, domain.PaymentAuthorized, paymentStatus(payments, id))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Other User's Order", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "999"}, nil).Once()
+
+		err := useCase.DeleteOrder(userCtx, id, 0)
+
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Already Deleted", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		deletedAt := time.Now()
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123", DeletedAt: &deletedAt}, nil).Once()
+
+		err := useCase.DeleteOrder(userCtx, id, 0)
+
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Admin", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "999"}, nil).Once()
+		mockRepo.On("Delete", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+			return o.ID == id && o.DeletedBy == "admin-1"
+		})).Return(nil).Once()
+
+		err := useCase.DeleteOrder(adminCtx, id, 0)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Version Mismatch", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123", Version: 3}, nil).Once()
+
+		err := useCase.DeleteOrder(userCtx, id, 2)
+
+		assert.ErrorIs(t, err, domain.ErrVersionMismatch)
+		assert.ErrorIs(t, err, domain.ErrPreconditionFailed)
+		mockRepo.AssertExpectations(t)
+	})
+
 	t.Run("Repository Error", func(t *testing.T) {
 		id := primitive.NewObjectID()
-		mockRepo.On("Delete", mock.Anything, id).Return(assert.AnError).Once()
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123", Version: 2}, nil).Once()
+		mockRepo.On("Delete", mock.Anything, mock.Anything).Return(assert.AnError).Once()
+
+		err := useCase.DeleteOrder(userCtx, id, 0)
+
+		assert.Error(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestRestoreOrder(t *testing.T) {
+	mockRepo := new(mockRepo.MockOrderRepository)
+	useCase := newTestUseCase(mockRepo, newTestInventory(), newTestPayments(), memory.NewOutbox())
+	deletedAt := time.Now()
+
+	t.Run("Success", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "999", Version: 3, DeletedAt: &deletedAt, DeletedBy: "999"}, nil).Once()
+		mockRepo.On("Restore", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+			return o.ID == id && o.Version == 3 && o.DeletedAt == nil && o.DeletedBy == ""
+		})).Return(nil).Once()
+
+		order, err := useCase.RestoreOrder(adminCtx, id, 3)
+
+		assert.NoError(t, err)
+		assert.False(t, order.IsDeleted())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unpaid Order Comes Back Cancelled", func(t *testing.T) {
+		outbox := memory.NewOutbox()
+		useCase := newTestUseCase(mockRepo, newTestInventory(), newTestPayments(), outbox)
+		id := primitive.NewObjectID()
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "999", Status: domain.OrderStatusConfirmed, Version: 3, DeletedAt: &deletedAt}, nil).Once()
+		mockRepo.On("Restore", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+			return o.ID == id && o.Status == domain.OrderStatusCancelled && o.DeletedAt == nil
+		})).Return(nil).Once()
+
+		order, err := useCase.RestoreOrder(adminCtx, id, 3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.OrderStatusCancelled, order.Status)
+		assert.Equal(t, int64(4), order.Version)
+		events := outbox.Events()
+		assert.Len(t, events, 3)
+		assert.Equal(t, domain.EventOrderCancelled, events[1].Type)
+		assert.Equal(t, domain.EventOrderRestored, events[2].Type)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Not Deleted", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "999"}, nil).Once()
+
+		_, err := useCase.RestoreOrder(adminCtx, id, 0)
+
+		assert.ErrorIs(t, err, domain.ErrOrderNotDeleted)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockRepo.On("GetByID", mock.Anything, id).Return(nil, nil).Once()
+
+		_, err := useCase.RestoreOrder(adminCtx, id, 0)
+
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Version Mismatch", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "999", Version: 4, DeletedAt: &deletedAt}, nil).Once()
+
+		_, err := useCase.RestoreOrder(adminCtx, id, 3)
+
+		assert.ErrorIs(t, err, domain.ErrVersionMismatch)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Requires Admin", func(t *testing.T) {
+		_, err := useCase.RestoreOrder(userCtx, primitive.NewObjectID(), 0)
+
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestUnauthenticated(t *testing.T) {
+	mockRepo := new(mockRepo.MockOrderRepository)
+	useCase := newTestUseCase(mockRepo, newTestInventory(), newTestPayments(), memory.NewOutbox())
+	ctx := context.Background()
+	id := primitive.NewObjectID()
+
+	assert.ErrorIs(t, useCase.CreateOrder(ctx, &domain.Order{}), domain.ErrUnauthorized)
+	_, err := useCase.GetOrder(ctx, id, false)
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	_, err = useCase.GetOrders(ctx, domain.OrderFilter{})
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	assert.ErrorIs(t, useCase.UpdateOrder(ctx, &domain.Order{ID: id}), domain.ErrUnauthorized)
+	_, err = useCase.TransitionOrder(ctx, id, domain.OrderStatusConfirmed)
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	assert.ErrorIs(t, useCase.DeleteOrder(ctx, id, 0), domain.ErrUnauthorized)
+	_, err = useCase.RestoreOrder(ctx, id, 0)
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestOrderEvents(t *testing.T) {
+	mockRepo := new(mockRepo.MockOrderRepository)
+	outbox := memory.NewOutbox()
+	useCase := newTestUseCase(mockRepo, newTestInventory(), newTestPayments(), outbox)
+
+	lastEvents := func(n int) []domain.OrderEvent {
+		events := outbox.Events()
+		return events[len(events)-n:]
+	}
+
+	t.Run("Order Created", func(t *testing.T) {
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+
+		order := &domain.Order{Items: []domain.OrderItem{{ProductID: "456", Quantity: 1}}}
+		assert.NoError(t, useCase.CreateOrder(userCtx, order))
+
+		events := lastEvents(2)
+		assert.Equal(t, domain.EventOrderStatusChanged, events[1].Type)
+		assert.Equal(t, domain.OrderStatusConfirmed, events[1].Status)
+
+		event := events[0]
+		assert.Equal(t, domain.EventOrderCreated, event.Type)
+		assert.Equal(t, "123", event.UserID)
+		assert.Equal(t, domain.OrderStatusPending, event.Status)
+		assert.Equal(t, usd(50000), event.TotalPrice)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Status Changed", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123", Status: domain.OrderStatusPending}, nil).Once()
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
 
-		err := useCase.DeleteOrder(context.Background(), id)
+		_, err := useCase.TransitionOrder(userCtx, id, domain.OrderStatusConfirmed)
+		assert.NoError(t, err)
+
+		event := lastEvents(1)[0]
+		assert.Equal(t, domain.EventOrderStatusChanged, event.Type)
+		assert.Equal(t, id, event.OrderID)
+		assert.Equal(t, domain.OrderStatusPending, event.PreviousStatus)
+		assert.Equal(t, domain.OrderStatusConfirmed, event.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Order Cancelled", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		status := domain.OrderStatusCancelled
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123", Status: domain.OrderStatusConfirmed}, nil).Once()
+		mockRepo.On("Patch", mock.Anything, id, mock.Anything).Return(&domain.Order{ID: id, UserID: "123", Status: status}, nil).Once()
+
+		_, err := useCase.PatchOrder(userCtx, id, domain.OrderPatch{Status: &status})
+		assert.NoError(t, err)
 
+		events := lastEvents(2)
+		assert.Equal(t, domain.EventOrderStatusChanged, events[0].Type)
+		assert.Equal(t, domain.EventOrderCancelled, events[1].Type)
+		assert.Equal(t, domain.OrderStatusConfirmed, events[1].PreviousStatus)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("No Event Without Status Change", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		before := len(outbox.Events())
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123", Status: domain.OrderStatusPending}, nil).Once()
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+
+		err := useCase.UpdateOrder(userCtx, &domain.Order{ID: id, Items: []domain.OrderItem{{ProductID: "456", Quantity: 2}}})
+		assert.NoError(t, err)
+
+		assert.Len(t, outbox.Events(), before)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("No Event When Write Fails", func(t *testing.T) {
+		before := len(outbox.Events())
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(assert.AnError).Once()
+
+		err := useCase.CreateOrder(userCtx, &domain.Order{Items: []domain.OrderItem{{ProductID: "456", Quantity: 1}}})
 		assert.Error(t, err)
+
+		assert.Len(t, outbox.Events(), before)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestOrderStock(t *testing.T) {
+	mockRepo := new(mockRepo.MockOrderRepository)
+	inventory := memory.NewInventory()
+	inventory.SetStock("456", 3)
+	payments := newTestPayments()
+	useCase := newTestUseCase(mockRepo, inventory, payments, memory.NewOutbox())
+
+	t.Run("Releases On Cancel", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		items := []domain.OrderItem{{ProductID: "456", Quantity: 1}}
+		inventory.Reserve(context.Background(), id, items)
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123", Items: items, Status: domain.OrderStatusPending}, nil).Once()
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+
+		_, err := useCase.TransitionOrder(userCtx, id, domain.OrderStatusCancelled)
+
+		assert.NoError(t, err)
+		assert.Equal(t, memory.ReservationReleased, inventory.ReservationStatus(id))
+		assert.Equal(t, 3, inventory.Available("456"))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Commits On Payment", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		items := []domain.OrderItem{{ProductID: "456", Quantity: 1}}
+		inventory.Reserve(context.Background(), id, items)
+		payments.Authorize(context.Background(), &domain.Order{ID: id, TotalPrice: usd(50000)})
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123", Items: items, TotalPrice: usd(50000), Status: domain.OrderStatusConfirmed}, nil).Once()
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+
+		_, err := useCase.TransitionOrder(userCtx, id, domain.OrderStatusPaid)
+
+		assert.NoError(t, err)
+		assert.Equal(t, memory.ReservationCommitted, inventory.ReservationStatus(id))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Payment After Release", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		items := []domain.OrderItem{{ProductID: "456", Quantity: 1}}
+		inventory.Reserve(context.Background(), id, items)
+		inventory.Release(context.Background(), id)
+		payments.Authorize(context.Background(), &domain.Order{ID: id, TotalPrice: usd(50000)})
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123", Items: items, TotalPrice: usd(50000), Status: domain.OrderStatusConfirmed}, nil).Once()
+
+		_, err := useCase.TransitionOrder(userCtx, id, domain.OrderStatusPaid)
+
+		assert.ErrorIs(t, err, domain.ErrReservationClosed)
+		assert.Equal(t, domain.PaymentRefunded, paymentStatus(payments, id))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Stock Stays Reserved When Capture Fails", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		items := []domain.OrderItem{{ProductID: "456", Quantity: 1}}
+		inventory.Reserve(context.Background(), id, items)
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123", Items: items, Status: domain.OrderStatusConfirmed}, nil).Once()
+
+		_, err := useCase.TransitionOrder(userCtx, id, domain.OrderStatusPaid)
+
+		assert.ErrorIs(t, err, domain.ErrPaymentNotFound)
+		assert.Equal(t, memory.ReservationReserved, inventory.ReservationStatus(id))
+		inventory.Release(context.Background(), id)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Re-reserves Changed Items", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		inventory.Reserve(context.Background(), id, []domain.OrderItem{{ProductID: "456", Quantity: 1}})
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123", Status: domain.OrderStatusPending, Version: 1}, nil).Once()
+		mockRepo.On("Patch", mock.Anything, id, mock.Anything).Return(&domain.Order{ID: id, UserID: "123", Status: domain.OrderStatusPending}, nil).Once()
+
+		_, err := useCase.PatchOrder(userCtx, id, domain.OrderPatch{Items: []domain.OrderItem{{ProductID: "456", Quantity: 2}}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, inventory.Available("456"))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Restores Items When Write Fails", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		items := []domain.OrderItem{{ProductID: "789", Quantity: 1}}
+		inventory.SetStock("789", 3)
+		inventory.Reserve(context.Background(), id, items)
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123", Items: items, Status: domain.OrderStatusPending, Version: 1}, nil).Once()
+		mockRepo.On("Patch", mock.Anything, id, mock.Anything).Return(nil, domain.ErrVersionMismatch).Once()
+
+		_, err := useCase.PatchOrder(userCtx, id, domain.OrderPatch{Items: []domain.OrderItem{{ProductID: "789", Quantity: 3}}})
+
+		assert.ErrorIs(t, err, domain.ErrVersionMismatch)
+		assert.Equal(t, 2, inventory.Available("789"))
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestOrderPayment(t *testing.T) {
+	mockRepo := new(mockRepo.MockOrderRepository)
+	inventory := newTestInventory()
+	payments := newTestPayments()
+	useCase := newTestUseCase(mockRepo, inventory, payments, memory.NewOutbox())
+
+	authorized := func(t *testing.T, status domain.OrderStatus) primitive.ObjectID {
+		id := primitive.NewObjectID()
+		_, err := payments.Authorize(context.Background(), &domain.Order{ID: id, TotalPrice: usd(50000)})
+		assert.NoError(t, err)
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123", TotalPrice: usd(50000), Status: status}, nil).Once()
+		return id
+	}
+
+	t.Run("Paid Only After Capture", func(t *testing.T) {
+		id := authorized(t, domain.OrderStatusConfirmed)
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+
+		order, err := useCase.TransitionOrder(userCtx, id, domain.OrderStatusPaid)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.OrderStatusPaid, order.Status)
+		assert.Equal(t, domain.PaymentCaptured, paymentStatus(payments, id))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Undone When Write Fails", func(t *testing.T) {
+		id := authorized(t, domain.OrderStatusConfirmed)
+		inventory.Reserve(context.Background(), id, []domain.OrderItem{{ProductID: "456", Quantity: 1}})
+		mockRepo.On("Update", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+			// The payment has been taken by the time the write is rejected.
+			assert.Equal(t, domain.PaymentCaptured, paymentStatus(payments, id))
+		}).Return(domain.ErrVersionMismatch).Once()
+
+		_, err := useCase.TransitionOrder(userCtx, id, domain.OrderStatusPaid)
+
+		assert.ErrorIs(t, err, domain.ErrVersionMismatch)
+		assert.Equal(t, domain.PaymentRefunded, paymentStatus(payments, id))
+		assert.Equal(t, memory.ReservationReserved, inventory.ReservationStatus(id))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Items Locked Once Authorized", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		items := []domain.OrderItem{{ProductID: "456", SKU: "SKU-456", UnitPrice: usd(50000), Quantity: 1, LineTotal: usd(50000)}}
+		confirmed := &domain.Order{ID: id, UserID: "123", Items: items, TotalPrice: usd(50000), Status: domain.OrderStatusConfirmed, Version: 2}
+		inventory.Reserve(context.Background(), id, items)
+		_, err := payments.Authorize(context.Background(), confirmed)
+		assert.NoError(t, err)
+		mockRepo.On("GetByID", mock.Anything, id).Return(confirmed, nil).Times(3)
+
+		_, err = useCase.PatchOrder(userCtx, id, domain.OrderPatch{Items: []domain.OrderItem{{ProductID: "456", Quantity: 3}}})
+		assert.ErrorIs(t, err, domain.ErrOrderItemsLocked)
+		err = useCase.UpdateOrder(userCtx, &domain.Order{ID: id, Items: []domain.OrderItem{{ProductID: "789", Quantity: 1}}})
+		assert.ErrorIs(t, err, domain.ErrOrderItemsLocked)
+
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+		order, err := useCase.TransitionOrder(userCtx, id, domain.OrderStatusPaid)
+
+		assert.NoError(t, err)
+		assert.Equal(t, usd(50000), order.TotalPrice)
+		assert.Equal(t, domain.PaymentCaptured, paymentStatus(payments, id))
+		assert.Equal(t, memory.ReservationCommitted, inventory.ReservationStatus(id))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Not Captured For Another Total", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		items := []domain.OrderItem{{ProductID: "456", Quantity: 1}}
+		inventory.Reserve(context.Background(), id, items)
+		_, err := payments.Authorize(context.Background(), &domain.Order{ID: id, TotalPrice: usd(50000)})
+		assert.NoError(t, err)
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123", Items: items, TotalPrice: usd(100000), Status: domain.OrderStatusConfirmed}, nil).Once()
+
+		_, err = useCase.TransitionOrder(userCtx, id, domain.OrderStatusPaid)
+
+		assert.ErrorIs(t, err, domain.ErrPaymentMismatch)
+		assert.Equal(t, domain.PaymentAuthorized, paymentStatus(payments, id))
+		assert.Equal(t, memory.ReservationReserved, inventory.ReservationStatus(id))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Not Paid Without Payment", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123", Status: domain.OrderStatusConfirmed}, nil).Once()
+
+		_, err := useCase.TransitionOrder(userCtx, id, domain.OrderStatusPaid)
+
+		assert.ErrorIs(t, err, domain.ErrPaymentNotFound)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Refund", func(t *testing.T) {
+		id := authorized(t, domain.OrderStatusPaid)
+		payments.Capture(context.Background(), id, usd(50000))
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+
+		_, err := useCase.TransitionOrder(userCtx, id, domain.OrderStatusRefunded)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.PaymentRefunded, paymentStatus(payments, id))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Voids On Cancel", func(t *testing.T) {
+		id := authorized(t, domain.OrderStatusConfirmed)
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+
+		_, err := useCase.TransitionOrder(userCtx, id, domain.OrderStatusCancelled)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.PaymentVoided, paymentStatus(payments, id))
 		mockRepo.AssertExpectations(t)
 	})
 }
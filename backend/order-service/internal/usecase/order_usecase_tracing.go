@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"order-service/internal/domain"
+)
+
+var tracer = otel.Tracer("order-service/internal/usecase")
+
+// tracedOrderUseCase wraps every orderUseCase call in a span, so a trace
+// shows how much of a request went to business logic as opposed to the
+// handler or MongoDB.
+type tracedOrderUseCase struct {
+	next domain.OrderUseCase
+}
+
+func NewTracedOrderUseCase(next domain.OrderUseCase) domain.OrderUseCase {
+	return &tracedOrderUseCase{next: next}
+}
+
+func (u *tracedOrderUseCase) CreateOrder(ctx context.Context, order *domain.Order) (err error) {
+	ctx, span := startSpan(ctx, "CreateOrder")
+	defer func() {
+		span.SetAttributes(attribute.String("order.id", order.ID.Hex()))
+		endSpan(span, err)
+	}()
+	return u.next.CreateOrder(ctx, order)
+}
+
+func (u *tracedOrderUseCase) GetOrder(ctx context.Context, id primitive.ObjectID, includeDeleted bool) (_ *domain.Order, err error) {
+	ctx, span := startSpan(ctx, "GetOrder", attribute.String("order.id", id.Hex()))
+	defer func() { endSpan(span, err) }()
+	return u.next.GetOrder(ctx, id, includeDeleted)
+}
+
+func (u *tracedOrderUseCase) GetOrders(ctx context.Context, filter domain.OrderFilter) (_ *domain.OrderPage, err error) {
+	ctx, span := startSpan(ctx, "GetOrders")
+	defer func() { endSpan(span, err) }()
+	return u.next.GetOrders(ctx, filter)
+}
+
+func (u *tracedOrderUseCase) UpdateOrder(ctx context.Context, order *domain.Order) (err error) {
+	ctx, span := startSpan(ctx, "UpdateOrder", attribute.String("order.id", order.ID.Hex()))
+	defer func() { endSpan(span, err) }()
+	return u.next.UpdateOrder(ctx, order)
+}
+
+func (u *tracedOrderUseCase) PatchOrder(ctx context.Context, id primitive.ObjectID, patch domain.OrderPatch) (_ *domain.Order, err error) {
+	ctx, span := startSpan(ctx, "PatchOrder", attribute.String("order.id", id.Hex()))
+	defer func() { endSpan(span, err) }()
+	return u.next.PatchOrder(ctx, id, patch)
+}
+
+func (u *tracedOrderUseCase) TransitionOrder(ctx context.Context, id primitive.ObjectID, status domain.OrderStatus) (_ *domain.Order, err error) {
+	ctx, span := startSpan(ctx, "TransitionOrder", attribute.String("order.id", id.Hex()), attribute.String("order.status", string(status)))
+	defer func() { endSpan(span, err) }()
+	return u.next.TransitionOrder(ctx, id, status)
+}
+
+func (u *tracedOrderUseCase) DeleteOrder(ctx context.Context, id primitive.ObjectID, version int64) (err error) {
+	ctx, span := startSpan(ctx, "DeleteOrder", attribute.String("order.id", id.Hex()))
+	defer func() { endSpan(span, err) }()
+	return u.next.DeleteOrder(ctx, id, version)
+}
+
+func (u *tracedOrderUseCase) RestoreOrder(ctx context.Context, id primitive.ObjectID, version int64) (_ *domain.Order, err error) {
+	ctx, span := startSpan(ctx, "RestoreOrder", attribute.String("order.id", id.Hex()))
+	defer func() { endSpan(span, err) }()
+	return u.next.RestoreOrder(ctx, id, version)
+}
+
+func startSpan(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "orderUseCase."+method, trace.WithAttributes(attrs...))
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"order-service/internal/domain"
+	"order-service/internal/repository/memory"
+	mockRepo "order-service/internal/repository/mock"
+)
+
+func TestTracedOrderUseCase(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	mockRepo := new(mockRepo.MockOrderRepository)
+	useCase := NewTracedOrderUseCase(newTestUseCase(mockRepo, newTestInventory(), newTestPayments(), memory.NewOutbox()))
+
+	t.Run("Success", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Order{ID: id, UserID: "123"}, nil).Once()
+
+		_, err := useCase.GetOrder(userCtx, id, false)
+
+		assert.NoError(t, err)
+		span := recorder.Ended()[len(recorder.Ended())-1]
+		assert.Equal(t, "orderUseCase.GetOrder", span.Name())
+		assert.Equal(t, codes.Unset, span.Status().Code)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockRepo.On("GetByID", mock.Anything, id).Return(nil, errors.New("connection reset")).Once()
+
+		_, err := useCase.GetOrder(userCtx, id, false)
+
+		assert.Error(t, err)
+		span := recorder.Ended()[len(recorder.Ended())-1]
+		assert.Equal(t, codes.Error, span.Status().Code)
+		assert.Len(t, span.Events(), 1)
+	})
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"order-service/internal/domain"
+)
+
+const relayBatchSize = 100
+
+// OutboxRelay moves events from the outbox to a publisher. Delivery is at
+// least once: an event published just before a crash is sent again.
+type OutboxRelay struct {
+	outbox    domain.OutboxRepository
+	publisher domain.EventPublisher
+	interval  time.Duration
+}
+
+func NewOutboxRelay(outbox domain.OutboxRepository, publisher domain.EventPublisher, interval time.Duration) *OutboxRelay {
+	return &OutboxRelay{
+		outbox:    outbox,
+		publisher: publisher,
+		interval:  interval,
+	}
+}
+
+// Run relays pending events every interval until ctx is cancelled.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RelayPending(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "outbox relay", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending publishes one batch of pending events in order and returns how
+// many were published. It stops at the first failure so events for an order
+// are never published out of order.
+func (r *OutboxRelay) RelayPending(ctx context.Context) (int, error) {
+	events, err := r.outbox.Pending(ctx, relayBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, event := range events {
+		if err := r.publisher.Publish(ctx, event); err != nil {
+			return i, err
+		}
+		if err := r.outbox.MarkPublished(ctx, event.ID); err != nil {
+			return i, err
+		}
+	}
+	return len(events), nil
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"order-service/internal/domain"
+	"order-service/internal/publisher"
+	"order-service/internal/repository/memory"
+)
+
+func TestOutboxRelay(t *testing.T) {
+	order := &domain.Order{ID: primitive.NewObjectID(), UserID: "123", Status: domain.OrderStatusPending}
+
+	t.Run("Publishes In Order", func(t *testing.T) {
+		outbox := memory.NewOutbox()
+		pub := publisher.NewMemoryPublisher()
+		relay := NewOutboxRelay(outbox, pub, time.Second)
+
+		created := domain.NewOrderEvent(domain.EventOrderCreated, order, "")
+		changed := domain.NewOrderEvent(domain.EventOrderStatusChanged, order, domain.OrderStatusPending)
+		assert.NoError(t, outbox.Add(context.Background(), created, changed))
+
+		n, err := relay.RelayPending(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
+		assert.Equal(t, []domain.OrderEvent{created, changed}, pub.Events())
+
+		pending, err := outbox.Pending(context.Background(), 10)
+		assert.NoError(t, err)
+		assert.Empty(t, pending)
+	})
+
+	t.Run("Keeps Events On Publish Failure", func(t *testing.T) {
+		outbox := memory.NewOutbox()
+		pub := publisher.NewMemoryPublisher()
+		pub.Err = assert.AnError
+		relay := NewOutboxRelay(outbox, pub, time.Second)
+
+		assert.NoError(t, outbox.Add(context.Background(), domain.NewOrderEvent(domain.EventOrderCreated, order, "")))
+
+		n, err := relay.RelayPending(context.Background())
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 0, n)
+		pending, _ := outbox.Pending(context.Background(), 10)
+		assert.Len(t, pending, 1)
+
+		pub.Err = nil
+		n, err = relay.RelayPending(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+	})
+
+	t.Run("Run Stops On Cancel", func(t *testing.T) {
+		outbox := memory.NewOutbox()
+		pub := publisher.NewMemoryPublisher()
+		relay := NewOutboxRelay(outbox, pub, time.Millisecond)
+		assert.NoError(t, outbox.Add(context.Background(), domain.NewOrderEvent(domain.EventOrderCreated, order, "")))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			relay.Run(ctx)
+			close(done)
+		}()
+
+		assert.Eventually(t, func() bool { return len(pub.Events()) == 1 }, time.Second, time.Millisecond)
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("relay did not stop after cancel")
+		}
+	})
+}
@@ -2,62 +2,256 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/yourusername/ecommerce/pkg/config"
+	"github.com/yourusername/ecommerce/pkg/health"
+	"github.com/yourusername/ecommerce/pkg/logging"
+	"github.com/yourusername/ecommerce/pkg/metrics"
+	"github.com/yourusername/ecommerce/pkg/tracing"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 
 	orderHttp "order-service/internal/delivery/http"
+	"order-service/internal/migration"
+	"order-service/internal/payment"
+	"order-service/internal/publisher"
+	catalogHttp "order-service/internal/repository/http"
 	orderRepo "order-service/internal/repository/mongo"
 	"order-service/internal/usecase"
 )
 
 func main() {
-	// MongoDB connection
-	mongoURI := os.Getenv("MONGODB_URI")
-	if mongoURI == "" {
-		mongoURI = "mongodb://localhost:27017"
+	// "order-service migrate [up|status] [flags]" only migrates the database.
+	args := os.Args[1:]
+	migrateCommand := ""
+	if len(args) > 0 && args[0] == "migrate" {
+		migrateCommand, args = "up", args[1:]
+		if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+			migrateCommand, args = args[0], args[1:]
+		}
+		if migrateCommand != "up" && migrateCommand != "status" {
+			log.Fatalf("unknown migrate command %q (want up or status)", migrateCommand)
+		}
+	}
+
+	cfg := Config{HTTP: config.HTTP{Port: "8083"}}
+	if err := config.Load(&cfg, args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		log.Fatal(err)
+	}
+	if _, err := logging.Setup(cfg.Log.Level, cfg.Log.Format); err != nil {
+		log.Fatal(err)
+	}
+	slog.Info("effective config", "config", config.LogValue(&cfg))
+
+	if err := run(cfg, migrateCommand); err != nil {
+		logging.Fatal(err)
+	}
+}
+
+// run starts the service and blocks until it is stopped. Failures are
+// returned rather than exiting on the spot, so the deferred cleanup still
+// runs.
+func run(cfg Config, migrateCommand string) error {
+	// SIGTERM (docker stop, rolling deploys) and SIGINT start a graceful
+	// shutdown.
+	stopCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Tracing
+	shutdownTracing, err := tracing.Setup(context.Background(), "order-service")
+	if err != nil {
+		return err
 	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("tracing shutdown", "error", err)
+		}
+	}()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	// MongoDB connection
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Mongo.ConnectTimeout)
 	defer cancel()
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI))
+	monitor := tracing.MongoMonitor(metrics.MongoMonitor())
+	client, err := mongo.Connect(ctx, cfg.Mongo.ClientOptions().SetMonitor(monitor))
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+	// The connect context expires soon after startup, so disconnect
+	// gets a deadline of its own.
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := client.Disconnect(ctx); err != nil {
+			slog.Error("mongo disconnect", "error", err)
+		}
+	}()
+
+	db := client.Database(cfg.Mongo.Database)
+
+	// Schema migrations. They may rewrite whole collections, so they are
+	// only bounded by a shutdown signal, not the connect timeout.
+	migrations, err := migration.NewRunner(db, migration.NewMongoStore(db.Collection(migration.CollectionName)), orderRepo.Migrations(cfg.OrdersCollection))
+	if err != nil {
+		return err
+	}
+	if migrateCommand != "" {
+		return runMigrate(stopCtx, migrations, migrateCommand)
+	}
+	if cfg.MigrateOnStart {
+		if _, err := migrations.Up(stopCtx); err != nil {
+			return err
+		}
+	} else if err := warnPendingMigrations(ctx, migrations); err != nil {
+		return err
 	}
-	defer client.Disconnect(ctx)
 
-	collection := client.Database("ecommerce").Collection("orders")
+	collection := db.Collection(cfg.OrdersCollection)
+	idempotencyCollection := db.Collection(orderRepo.IdempotencyCollection)
+	outboxCollection := db.Collection(orderRepo.OutboxCollection)
+	sagaCollection := db.Collection(orderRepo.SagaCollection)
+	paymentCollection := db.Collection(orderRepo.PaymentCollection)
+
+	// Product service
+	// Reservations need a service token; the catalog and probes ignore it.
+	productTransport := catalogHttp.NewServiceTokenTransport(otelhttp.NewTransport(http.DefaultTransport), "order-service", cfg.JWTSecret)
+	productClient := &http.Client{Timeout: cfg.ProductServiceTimeout, Transport: logging.NewTransport(productTransport)}
+	productCatalog := catalogHttp.NewHTTPProductCatalog(cfg.ProductServiceURL, productClient)
+	// A zero TTL leaves reservation expiry to the product service.
+	inventory := catalogHttp.NewHTTPInventory(cfg.ProductServiceURL, productClient, 0)
 
 	// Initialize layers
+	idempotencyStore := orderRepo.NewMongoIdempotencyStore(idempotencyCollection)
+	outbox := orderRepo.NewMongoOutboxRepository(outboxCollection)
+	txManager := orderRepo.NewMongoTxManager(client)
+	sagaRepo := orderRepo.NewMongoSagaRepository(sagaCollection)
+	paymentRepo := orderRepo.NewMongoPaymentRepository(paymentCollection)
 	orderRepo := orderRepo.NewMongoOrderRepository(collection)
-	orderUseCase := usecase.NewOrderUseCase(orderRepo)
+	// No real payment provider is integrated yet; the fake gateway approves
+	// every amount except those ending in .51 or .52, and trusts the stored
+	// payments for authorizations made before a restart.
+	payments := payment.NewService(payment.NewFakeGateway(), paymentRepo)
+	checkout := usecase.NewCheckoutSaga(orderRepo, sagaRepo, inventory, payments, outbox, txManager)
+	orderUseCase := usecase.NewTracedOrderUseCase(
+		usecase.NewOrderUseCase(orderRepo, productCatalog, inventory, payments, checkout, outbox, txManager),
+	)
+
+	// Authentication
+	verifier, err := orderHttp.NewTokenVerifier(cfg.JWTSecret, cfg.JWTJWKSFile)
+	if err != nil {
+		return err
+	}
+
+	// Background workers. They are stopped as part of the shutdown below,
+	// which nothing past this point returns before.
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+
+	// Outbox relay
+	relay := usecase.NewOutboxRelay(outbox, publisher.NewLogPublisher(slog.Default()), time.Second)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		relay.Run(workersCtx)
+	}()
+
+	// Checkouts interrupted by a restart are resumed once they have been
+	// idle for a minute.
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		checkout.Run(workersCtx, 10*time.Second, time.Minute)
+	}()
 
 	// HTTP Server
 	r := mux.NewRouter()
+	r.Use(otelmux.Middleware("order-service"))
+
+	// Prometheus metrics
+	r.Handle("/metrics", metrics.Handler())
 
-	// Health check
-	r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	})
+	// Liveness and readiness probes; /health is kept for existing callers.
+	probes := health.NewChecker(2*time.Second, 5*time.Second)
+	probes.Add("mongodb", health.Mongo(client))
+	probes.Add("product-service", health.HTTP(productClient, strings.TrimRight(cfg.ProductServiceURL, "/")+"/livez"))
+	r.Handle("/livez", probes.Livez()).Methods(http.MethodGet)
+	r.Handle("/health", probes.Livez()).Methods(http.MethodGet)
+	r.Handle("/readyz", probes.Readyz()).Methods(http.MethodGet)
 
 	// Register routes
-	orderHttp.NewOrderHandler(r, orderUseCase)
+	api := r.NewRoute().Subrouter()
+	api.Use(orderHttp.AuthMiddleware(verifier))
+	orderHttp.NewOrderHandler(api, orderUseCase, idempotencyStore)
 
 	// Start server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8083"
+	srv := cfg.HTTP.Server(orderHttp.Instrument(r, cfg.Log.AccessLog))
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("Order service is running", "port", cfg.HTTP.Port)
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	var listenErr error
+	select {
+	case listenErr = <-serveErr:
+		// ListenAndServe only returns before Shutdown when it cannot serve
+		// at all, e.g. the port is taken.
+	case <-stopCtx.Done():
+		slog.Info("shutting down")
+
+		// Fail readiness first and keep serving for a moment, so Kong and
+		// other probers take the instance out of rotation before connections
+		// close.
+		probes.SetShuttingDown()
+		time.Sleep(cfg.ShutdownDelay)
 	}
 
-	log.Printf("Order service is running on port %s", port)
-	if err := http.ListenAndServe(":"+port, r); err != nil {
-		log.Fatal(err)
+	// Stop accepting connections and let in-flight requests finish, then stop
+	// the workers, all within the shutdown timeout. The deferred calls then
+	// disconnect Mongo and flush spans.
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancelShutdown()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("http shutdown", "error", err)
+	}
+	stopWorkers()
+	if err := waitFor(shutdownCtx, &workers); err != nil {
+		slog.Error("background workers did not stop", "error", err)
+	} else {
+		slog.Info("background workers stopped")
+	}
+	return listenErr
+}
+
+// waitFor waits for wg, giving up when ctx is done.
+func waitFor(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 }
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"order-service/internal/migration"
+)
+
+// runMigrate runs the "migrate" subcommand: "up" applies the pending
+// migrations and "status" lists them all.
+func runMigrate(ctx context.Context, runner *migration.Runner, command string) error {
+	switch command {
+	case "up":
+		n, err := runner.Up(ctx)
+		if err != nil {
+			return err
+		}
+		slog.InfoContext(ctx, "migrations complete", "applied", n)
+		return nil
+	case "status":
+		statuses, err := runner.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tAPPLIED AT\tDESCRIPTION")
+		for _, s := range statuses {
+			applied := "pending"
+			if s.Applied != nil {
+				applied = s.Applied.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, applied, s.Description)
+		}
+		return w.Flush()
+	}
+	return fmt.Errorf("unknown migrate command %q", command)
+}
+
+// warnPendingMigrations logs the migrations a deploy with MIGRATE_ON_START
+// off still has to run.
+func warnPendingMigrations(ctx context.Context, runner *migration.Runner) error {
+	statuses, err := runner.Status(ctx)
+	if err != nil {
+		return err
+	}
+	for _, s := range statuses {
+		if s.Applied == nil {
+			slog.WarnContext(ctx, "migration pending; run \"order-service migrate\"", "version", s.Version, "description", s.Description)
+		}
+	}
+	return nil
+}
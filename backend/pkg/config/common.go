@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// HTTP holds the listener settings every service shares. Set Port before
+// Load to give a service its own default.
+type HTTP struct {
+	Port              string        `yaml:"port" env:"PORT" flag:"port" required:"true" usage:"HTTP port"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT" flag:"http-read-timeout" default:"10s" usage:"max time to read a request"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT" flag:"http-read-header-timeout" default:"5s" usage:"max time to read request headers"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT" flag:"http-write-timeout" default:"30s" usage:"max time to write a response"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT" flag:"http-idle-timeout" default:"120s" usage:"max idle time of keep-alive connections"`
+}
+
+func (h *HTTP) Validate() error {
+	if n, err := strconv.Atoi(h.Port); err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("PORT %q is not a valid port", h.Port)
+	}
+	return nil
+}
+
+// Server returns an http.Server for handler with the configured address and
+// timeouts.
+func (h HTTP) Server(handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              ":" + h.Port,
+		Handler:           handler,
+		ReadTimeout:       h.ReadTimeout,
+		ReadHeaderTimeout: h.ReadHeaderTimeout,
+		WriteTimeout:      h.WriteTimeout,
+		IdleTimeout:       h.IdleTimeout,
+	}
+}
+
+// Mongo holds the MongoDB connection settings.
+type Mongo struct {
+	URI            string        `yaml:"uri" env:"MONGODB_URI" flag:"mongodb-uri" default:"mongodb://localhost:27017" required:"true" secret:"url" usage:"MongoDB connection string"`
+	Database       string        `yaml:"database" env:"MONGODB_DATABASE" flag:"mongodb-database" default:"ecommerce" required:"true" usage:"MongoDB database"`
+	MaxPoolSize    uint64        `yaml:"max_pool_size" env:"MONGODB_MAX_POOL_SIZE" flag:"mongodb-max-pool-size" default:"100" usage:"max connections per server"`
+	MinPoolSize    uint64        `yaml:"min_pool_size" env:"MONGODB_MIN_POOL_SIZE" flag:"mongodb-min-pool-size" usage:"connections kept open per server"`
+	ConnectTimeout time.Duration `yaml:"connect_timeout" env:"MONGODB_CONNECT_TIMEOUT" flag:"mongodb-connect-timeout" default:"10s" usage:"timeout for the initial connection and startup work"`
+}
+
+func (m *Mongo) Validate() error {
+	if !strings.HasPrefix(m.URI, "mongodb://") && !strings.HasPrefix(m.URI, "mongodb+srv://") {
+		return fmt.Errorf("MONGODB_URI must start with mongodb:// or mongodb+srv://")
+	}
+	if m.MaxPoolSize != 0 && m.MinPoolSize > m.MaxPoolSize {
+		return fmt.Errorf("MONGODB_MIN_POOL_SIZE %d is above MONGODB_MAX_POOL_SIZE %d", m.MinPoolSize, m.MaxPoolSize)
+	}
+	return nil
+}
+
+// ClientOptions returns driver options for the configured URI and pool.
+func (m Mongo) ClientOptions() *options.ClientOptions {
+	return options.Client().
+		ApplyURI(m.URI).
+		SetMaxPoolSize(m.MaxPoolSize).
+		SetMinPoolSize(m.MinPoolSize).
+		SetConnectTimeout(m.ConnectTimeout)
+}
+
+// Log holds the logging settings.
+type Log struct {
+	Level     string `yaml:"level" env:"LOG_LEVEL" flag:"log-level" default:"info" usage:"debug, info, warn or error"`
+	Format    string `yaml:"format" env:"LOG_FORMAT" flag:"log-format" default:"json" usage:"json or text"`
+	AccessLog bool   `yaml:"access_log" env:"ACCESS_LOG" flag:"access-log" default:"true" usage:"log every request"`
+}
+
+func (l *Log) Validate() error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(l.Level)); err != nil {
+		return fmt.Errorf("LOG_LEVEL %q must be debug, info, warn or error", l.Level)
+	}
+	if l.Format != "json" && l.Format != "text" {
+		return fmt.Errorf("LOG_FORMAT %q must be json or text", l.Format)
+	}
+	return nil
+}
@@ -0,0 +1,297 @@
+// Package config loads a service's settings into a typed struct.
+//
+// Every leaf field is described by struct tags:
+//
+//	Port string `yaml:"port" env:"PORT" flag:"port" default:"8080" required:"true" usage:"HTTP port"`
+//
+// Values are applied in increasing precedence: the default (a value already
+// in the struct, or the default tag), the YAML file named by -config or
+// CONFIG_FILE, the environment (plus a .env file, which never overrides real
+// variables) and finally command-line flags. Fields tagged secret:"true" are
+// masked by Redacted; secret:"url" only masks the password of a URL.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Validator is implemented by config structs, at any depth, with rules the
+// tags cannot express. Load calls it after all sources were applied.
+type Validator interface {
+	Validate() error
+}
+
+const redacted = "****"
+
+type field struct {
+	value    reflect.Value
+	name     string // Go path, e.g. Mongo.URI
+	env      string
+	flag     string
+	def      string
+	usage    string
+	required bool
+	secret   string
+}
+
+// key names the field in messages, preferring the setting users write.
+func (f field) key() string {
+	if f.env != "" {
+		return f.env
+	}
+	if f.flag != "" {
+		return "-" + f.flag
+	}
+	return f.name
+}
+
+// Load fills cfg, a pointer to a struct, from all sources and validates it.
+// args are the command-line arguments without the program name. A -h flag
+// prints the usage and returns flag.ErrHelp.
+func Load(cfg interface{}, args []string) error {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config: Load needs a pointer to a struct, got %T", cfg)
+	}
+	fields := collect(v.Elem(), "")
+
+	for _, f := range fields {
+		if f.def != "" && f.value.IsZero() {
+			if err := set(f.value, f.def); err != nil {
+				return fmt.Errorf("config: default of %s: %w", f.name, err)
+			}
+		}
+	}
+
+	fs := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	file := fs.String("config", os.Getenv("CONFIG_FILE"), "YAML config file")
+	flags := map[string]*string{}
+	for _, f := range fields {
+		if f.flag != "" {
+			flags[f.flag] = fs.String(f.flag, "", usage(f))
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *file != "" {
+		if err := loadFile(cfg, *file); err != nil {
+			return err
+		}
+	}
+
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("config: .env: %w", err)
+	}
+
+	var errs []error
+	for _, f := range fields {
+		if f.env == "" {
+			continue
+		}
+		if s, ok := os.LookupEnv(f.env); ok {
+			if err := set(f.value, s); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", f.env, err))
+			}
+		}
+	}
+	fs.Visit(func(fl *flag.Flag) {
+		s, ok := flags[fl.Name]
+		if !ok {
+			return
+		}
+		for _, f := range fields {
+			if f.flag == fl.Name {
+				if err := set(f.value, *s); err != nil {
+					errs = append(errs, fmt.Errorf("-%s: %w", f.flag, err))
+				}
+			}
+		}
+	})
+	if len(errs) > 0 {
+		return fmt.Errorf("config: %w", errors.Join(errs...))
+	}
+
+	return validate(v.Elem(), fields)
+}
+
+func loadFile(cfg interface{}, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil {
+		return fmt.Errorf("config: %s: %w", path, err)
+	}
+	return nil
+}
+
+func validate(v reflect.Value, fields []field) error {
+	var errs []error
+	for _, f := range fields {
+		if f.required && f.value.IsZero() {
+			errs = append(errs, fmt.Errorf("%s is required", f.key()))
+		}
+	}
+	for _, val := range validators(v) {
+		if err := val.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("config: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// validators returns the Validators in v, nested ones first.
+func validators(v reflect.Value) []Validator {
+	var out []Validator
+	for i := 0; i < v.NumField(); i++ {
+		fv := v.Field(i)
+		if v.Type().Field(i).IsExported() && isSection(fv) {
+			out = append(out, validators(fv)...)
+		}
+	}
+	if val, ok := v.Addr().Interface().(Validator); ok {
+		out = append(out, val)
+	}
+	return out
+}
+
+// isSection reports whether v is a nested group of settings rather than a
+// single value.
+func isSection(v reflect.Value) bool {
+	return v.Kind() == reflect.Struct && v.Type() != reflect.TypeOf(time.Duration(0))
+}
+
+func collect(v reflect.Value, prefix string) []field {
+	var out []field
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		if isSection(fv) {
+			out = append(out, collect(fv, prefix+sf.Name+".")...)
+			continue
+		}
+		out = append(out, field{
+			value:    fv,
+			name:     prefix + sf.Name,
+			env:      sf.Tag.Get("env"),
+			flag:     sf.Tag.Get("flag"),
+			def:      sf.Tag.Get("default"),
+			usage:    sf.Tag.Get("usage"),
+			required: sf.Tag.Get("required") == "true",
+			secret:   sf.Tag.Get("secret"),
+		})
+	}
+	return out
+}
+
+func usage(f field) string {
+	u := f.usage
+	if f.env != "" {
+		u = strings.TrimSpace(u + " (env " + f.env + ")")
+	}
+	return u
+}
+
+func set(v reflect.Value, s string) error {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// Redacted renders the effective settings of cfg, one KEY=value per line in
+// field order, with secrets masked.
+func Redacted(cfg interface{}) string {
+	var b strings.Builder
+	for _, f := range collect(reflect.Indirect(reflect.ValueOf(cfg)), "") {
+		fmt.Fprintf(&b, "%s=%s\n", f.key(), redact(f))
+	}
+	return b.String()
+}
+
+// LogValue is Redacted as a slog group, for the startup log line.
+func LogValue(cfg interface{}) slog.Value {
+	var attrs []slog.Attr
+	for _, f := range collect(reflect.Indirect(reflect.ValueOf(cfg)), "") {
+		attrs = append(attrs, slog.String(f.key(), redact(f)))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+func redact(f field) string {
+	s := fmt.Sprint(f.value.Interface())
+	if s == "" {
+		return s
+	}
+	switch f.secret {
+	case "true":
+		return redacted
+	case "url":
+		u, err := url.Parse(s)
+		if err != nil {
+			return redacted
+		}
+		if _, ok := u.User.Password(); !ok {
+			return s
+		}
+		// UserPassword would percent-encode the mask, so splice it in.
+		u.User = url.User(u.User.Username())
+		return strings.Replace(u.String(), "@", ":"+redacted+"@", 1)
+	}
+	return s
+}
@@ -0,0 +1,195 @@
+// Package health serves the liveness (/livez) and readiness (/readyz) probes.
+// Liveness only says the process is serving; readiness runs the registered
+// dependency checks and fails while any of them does or once shutdown began.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// Check reports whether one dependency is usable.
+type Check func(ctx context.Context) error
+
+// Status values in probe responses.
+const (
+	StatusOK           = "ok"
+	StatusUnavailable  = "unavailable"
+	StatusShuttingDown = "shutting_down"
+	StatusUp           = "up"
+	StatusDown         = "down"
+)
+
+// Result is the outcome of one dependency check.
+type Result struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report is the /readyz response body.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker runs the readiness checks of a service. Results are cached for a
+// short while so frequent probes from several sources do not hammer the
+// dependencies.
+type Checker struct {
+	timeout  time.Duration
+	cacheTTL time.Duration
+	checks   []namedCheck
+	now      func() time.Time
+
+	shuttingDown atomic.Bool
+
+	mu       sync.Mutex
+	cached   map[string]Result
+	cachedAt time.Time
+}
+
+// NewChecker returns a Checker that gives each check up to timeout and reuses
+// results for cacheTTL.
+func NewChecker(timeout, cacheTTL time.Duration) *Checker {
+	return &Checker{timeout: timeout, cacheTTL: cacheTTL, now: time.Now}
+}
+
+// Add registers a dependency check under name. Call it before serving.
+func (c *Checker) Add(name string, check Check) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// SetShuttingDown makes readiness fail from now on, so load balancers stop
+// routing to the instance while it drains.
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+// Ready runs the checks (or reuses recent results) and reports whether the
+// service can take traffic.
+func (c *Checker) Ready(ctx context.Context) (Report, bool) {
+	results := c.results(ctx)
+
+	report := Report{Status: StatusOK, Checks: results}
+	for _, r := range results {
+		if r.Status != StatusUp {
+			report.Status = StatusUnavailable
+		}
+	}
+	if c.shuttingDown.Load() {
+		report.Status = StatusShuttingDown
+	}
+	return report, report.Status == StatusOK
+}
+
+func (c *Checker) results(ctx context.Context) map[string]Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cached != nil && c.now().Sub(c.cachedAt) < c.cacheTTL {
+		return c.cached
+	}
+
+	// The results are shared with every prober for cacheTTL, so one that
+	// hangs up early must not cancel the checks and get them cached as down.
+	ctx = context.WithoutCancel(ctx)
+	results := make(map[string]Result, len(c.checks))
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for _, nc := range c.checks {
+		wg.Add(1)
+		go func(nc namedCheck) {
+			defer wg.Done()
+			r := c.run(ctx, nc.check)
+			mu.Lock()
+			results[nc.name] = r
+			mu.Unlock()
+		}(nc)
+	}
+	wg.Wait()
+
+	c.cached, c.cachedAt = results, c.now()
+	return results
+}
+
+func (c *Checker) run(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	r := Result{Status: StatusUp, LatencyMS: float64(time.Since(start).Microseconds()) / 1000}
+	if err != nil {
+		r.Status, r.Error = StatusDown, err.Error()
+	}
+	return r
+}
+
+// Livez answers 200 as long as the process can serve requests.
+func (c *Checker) Livez() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": StatusOK})
+	})
+}
+
+// Readyz answers 200 with the check report when the service is ready and 503
+// otherwise.
+func (c *Checker) Readyz() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report, ready := c.Ready(r.Context())
+		status := http.StatusOK
+		if !ready {
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, status, report)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// Mongo pings the primary.
+func Mongo(client *mongo.Client) Check {
+	return func(ctx context.Context) error {
+		return client.Ping(ctx, readpref.Primary())
+	}
+}
+
+// HTTP expects a 2xx from a GET on url, typically another service's /livez.
+func HTTP(client *http.Client, url string) Check {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("%s returned %d", url, resp.StatusCode)
+		}
+		return nil
+	}
+}
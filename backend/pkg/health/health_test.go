@@ -0,0 +1,131 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readyz(t *testing.T, c *Checker) (int, Report) {
+	rec := httptest.NewRecorder()
+	c.Readyz().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var report Report
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	return rec.Code, report
+}
+
+func TestReadyz(t *testing.T) {
+	t.Run("Ready", func(t *testing.T) {
+		c := NewChecker(time.Second, 0)
+		c.Add("mongodb", func(ctx context.Context) error { return nil })
+
+		code, report := readyz(t, c)
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, StatusOK, report.Status)
+		assert.Equal(t, StatusUp, report.Checks["mongodb"].Status)
+	})
+
+	t.Run("Dependency Down", func(t *testing.T) {
+		c := NewChecker(time.Second, 0)
+		c.Add("mongodb", func(ctx context.Context) error { return nil })
+		c.Add("product-service", func(ctx context.Context) error { return errors.New("connection refused") })
+
+		code, report := readyz(t, c)
+
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, StatusUnavailable, report.Status)
+		assert.Equal(t, StatusUp, report.Checks["mongodb"].Status)
+		assert.Equal(t, Result{Status: StatusDown, Error: "connection refused", LatencyMS: report.Checks["product-service"].LatencyMS}, report.Checks["product-service"])
+	})
+
+	t.Run("Check Times Out", func(t *testing.T) {
+		c := NewChecker(10*time.Millisecond, 0)
+		c.Add("mongodb", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		code, report := readyz(t, c)
+
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, StatusDown, report.Checks["mongodb"].Status)
+	})
+
+	t.Run("Prober Hangs Up", func(t *testing.T) {
+		c := NewChecker(time.Second, time.Minute)
+		c.Add("mongodb", func(ctx context.Context) error { return ctx.Err() })
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		c.Ready(ctx)
+
+		code, report := readyz(t, c)
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, StatusUp, report.Checks["mongodb"].Status)
+	})
+
+	t.Run("Shutting Down", func(t *testing.T) {
+		c := NewChecker(time.Second, 0)
+		c.Add("mongodb", func(ctx context.Context) error { return nil })
+		c.SetShuttingDown()
+
+		code, report := readyz(t, c)
+
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, StatusShuttingDown, report.Status)
+	})
+}
+
+func TestReadyzCachesResults(t *testing.T) {
+	now := time.Now()
+	calls := 0
+	c := NewChecker(time.Second, 2*time.Second)
+	c.now = func() time.Time { return now }
+	c.Add("mongodb", func(ctx context.Context) error {
+		calls++
+		return nil
+	})
+
+	readyz(t, c)
+	now = now.Add(time.Second)
+	readyz(t, c)
+	assert.Equal(t, 1, calls)
+
+	now = now.Add(2 * time.Second)
+	readyz(t, c)
+	assert.Equal(t, 2, calls)
+}
+
+func TestLivez(t *testing.T) {
+	c := NewChecker(time.Second, 0)
+	c.Add("mongodb", func(ctx context.Context) error { return errors.New("down") })
+	c.SetShuttingDown()
+
+	rec := httptest.NewRecorder()
+	c.Livez().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+}
+
+func TestHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/livez" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	assert.NoError(t, HTTP(server.Client(), server.URL+"/livez")(context.Background()))
+	assert.EqualError(t, HTTP(server.Client(), server.URL+"/readyz")(context.Background()), server.URL+"/readyz returned 503")
+}
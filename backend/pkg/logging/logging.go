@@ -0,0 +1,99 @@
+// Package logging sets up structured log/slog logging and carries the request
+// ID through contexts and outgoing HTTP calls, so the lines a request leaves
+// in every service can be joined on request_id (and trace_id when tracing is
+// on).
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Log formats accepted by Setup.
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Setup installs a logger writing to stdout at level ("debug", "info",
+// "warn" or "error") in format as the slog default. The stdlib log package
+// writes through it as well.
+func Setup(level, format string) (*slog.Logger, error) {
+	handler, err := NewHandler(os.Stdout, level, format)
+	if err != nil {
+		return nil, err
+	}
+	logger := slog.New(handler)
+	slog.SetDefault(logger)
+	return logger, nil
+}
+
+// NewHandler returns a handler that adds request_id, trace_id and span_id
+// from the context to every record.
+func NewHandler(w io.Writer, level, format string) (slog.Handler, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q", level)
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		return contextHandler{slog.NewJSONHandler(w, opts)}, nil
+	case FormatText:
+		return contextHandler{slog.NewTextHandler(w, opts)}, nil
+	}
+	return nil, fmt.Errorf("invalid log format %q", format)
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID().String()), slog.String("span_id", sc.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// Access logs one handled request. route is the route template, or empty when
+// none matched.
+func Access(ctx context.Context, method, path, route string, status int, elapsed time.Duration) {
+	level := slog.LevelInfo
+	if status >= 500 {
+		level = slog.LevelError
+	}
+	slog.Default().LogAttrs(ctx, level, "request",
+		slog.String("method", method),
+		slog.String("path", path),
+		slog.String("route", route),
+		slog.Int("status", status),
+		slog.Float64("duration_ms", float64(elapsed.Microseconds())/1000),
+	)
+}
+
+// Fatal logs err at error level and exits, like log.Fatal. Before Go 1.22 the
+// stdlib log package always writes at info level through slog.
+func Fatal(err error) {
+	slog.Error("fatal error", "error", err)
+	os.Exit(1)
+}
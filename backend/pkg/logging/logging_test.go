@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNewHandler(t *testing.T) {
+	t.Run("Adds Context Attributes", func(t *testing.T) {
+		var buf bytes.Buffer
+		handler, err := NewHandler(&buf, "info", FormatJSON)
+		require.NoError(t, err)
+
+		traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+		spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+		ctx := trace.ContextWithSpanContext(WithRequestID(context.Background(), "req-1"),
+			trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+		slog.New(handler).With("service", "order-service").InfoContext(ctx, "hello")
+
+		var line map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+		assert.Equal(t, "hello", line["msg"])
+		assert.Equal(t, "order-service", line["service"])
+		assert.Equal(t, "req-1", line["request_id"])
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", line["trace_id"])
+		assert.Equal(t, "00f067aa0ba902b7", line["span_id"])
+	})
+
+	t.Run("Level", func(t *testing.T) {
+		var buf bytes.Buffer
+		handler, err := NewHandler(&buf, "warn", FormatText)
+		require.NoError(t, err)
+
+		logger := slog.New(handler)
+		logger.Info("dropped")
+		logger.Warn("kept")
+
+		assert.NotContains(t, buf.String(), "dropped")
+		assert.Contains(t, buf.String(), "msg=kept")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := NewHandler(&bytes.Buffer{}, "loud", FormatJSON)
+		assert.EqualError(t, err, `invalid log level "loud"`)
+
+		_, err = NewHandler(&bytes.Buffer{}, "info", "xml")
+		assert.EqualError(t, err, `invalid log format "xml"`)
+	})
+}
+
+func TestRequestIDFromHeader(t *testing.T) {
+	header := http.Header{}
+	header.Set(HeaderRequestID, "kong-1234")
+	assert.Equal(t, "kong-1234", RequestIDFromHeader(header))
+
+	for _, id := range []string{"", "has space", "new\nline", strings.Repeat("a", 129)} {
+		header.Set(HeaderRequestID, id)
+		generated := RequestIDFromHeader(header)
+		assert.Len(t, generated, 32, "for %q", id)
+	}
+}
+
+func TestTransport(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get(HeaderRequestID))
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: NewTransport(nil)}
+
+	req, _ := http.NewRequestWithContext(WithRequestID(context.Background(), "req-1"), http.MethodGet, server.URL, nil)
+	_, err := client.Do(req)
+	require.NoError(t, err)
+	assert.Empty(t, req.Header.Get(HeaderRequestID), "caller's request is left untouched")
+
+	req, _ = http.NewRequest(http.MethodGet, server.URL, nil)
+	_, err = client.Do(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"req-1", ""}, received)
+}
@@ -0,0 +1,80 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// HeaderRequestID carries the request ID between Kong and the services.
+const HeaderRequestID = "X-Request-ID"
+
+// maxRequestIDLength bounds incoming IDs so clients cannot bloat every log
+// line of a request.
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context carrying id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID in ctx, or "".
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// RequestIDFromHeader returns the X-Request-ID of an incoming request, or a
+// new ID when it is missing or not a plain printable token.
+func RequestIDFromHeader(h http.Header) string {
+	if id := h.Get(HeaderRequestID); validRequestID(id) {
+		return id
+	}
+	return NewRequestID()
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// NewRequestID returns a random 128-bit ID in hex.
+func NewRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// NewTransport returns a RoundTripper that sends the request ID in the
+// request's context as X-Request-ID, so downstream services log the same ID.
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return transport{base: base}
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+func (t transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	id := RequestID(r.Context())
+	if id == "" || r.Header.Get(HeaderRequestID) != "" {
+		return t.base.RoundTrip(r)
+	}
+	// A RoundTripper must not modify the caller's request.
+	r = r.Clone(r.Context())
+	r.Header.Set(HeaderRequestID, id)
+	return t.base.RoundTrip(r)
+}
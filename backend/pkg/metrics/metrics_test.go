@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+func TestObserveHTTP(t *testing.T) {
+	ObserveHTTP("GET", "/api/v1/orders/{id}", 200, 20*time.Millisecond)
+	ObserveHTTP("GET", "", 404, time.Millisecond)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(httpRequests.WithLabelValues("GET", "/api/v1/orders/{id}", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(httpRequests.WithLabelValues("GET", UnmatchedRoute, "404")))
+	assert.Equal(t, uint64(1), sampleCount(t, httpDuration.WithLabelValues("GET", "/api/v1/orders/{id}")))
+}
+
+func TestMongoMonitor(t *testing.T) {
+	monitor := MongoMonitor()
+	ctx := context.Background()
+	command, err := bson.Marshal(bson.D{{Key: "find", Value: "orders"}, {Key: "filter", Value: bson.D{}}})
+	require.NoError(t, err)
+
+	monitor.Started(ctx, &event.CommandStartedEvent{Command: command, CommandName: "find", RequestID: 1})
+	monitor.Succeeded(ctx, &event.CommandSucceededEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{RequestID: 1, CommandName: "find", Duration: 5 * time.Millisecond},
+	})
+	monitor.Failed(ctx, &event.CommandFailedEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{RequestID: 2, CommandName: "insert", Duration: time.Millisecond},
+	})
+
+	assert.Equal(t, uint64(1), sampleCount(t, mongoDuration.WithLabelValues("orders", "find", "ok")))
+	assert.Equal(t, uint64(1), sampleCount(t, mongoDuration.WithLabelValues("", "insert", "error")))
+}
+
+func sampleCount(t *testing.T, observer prometheus.Observer) uint64 {
+	var m dto.Metric
+	require.NoError(t, observer.(prometheus.Metric).Write(&m))
+	return m.GetHistogram().GetSampleCount()
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// MongoMonitor returns a command monitor that records the latency of every
+// command the repositories send. Pass it to options.Client().SetMonitor.
+func MongoMonitor() *event.CommandMonitor {
+	m := &mongoMonitor{}
+	return &event.CommandMonitor{
+		Started:   m.started,
+		Succeeded: m.succeeded,
+		Failed:    m.failed,
+	}
+}
+
+type mongoCommand struct {
+	collection string
+	command    string
+}
+
+// mongoMonitor remembers each in-flight command's collection, which only the
+// started event carries, until the command finishes.
+type mongoMonitor struct {
+	inflight sync.Map // request ID -> mongoCommand
+}
+
+func (m *mongoMonitor) started(_ context.Context, e *event.CommandStartedEvent) {
+	cmd := mongoCommand{command: e.CommandName}
+	// For CRUD commands the first element is {<command>: <collection>}.
+	if elems, err := e.Command.Elements(); err == nil && len(elems) > 0 {
+		if collection, ok := elems[0].Value().StringValueOK(); ok {
+			cmd.collection = collection
+		}
+	}
+	m.inflight.Store(e.RequestID, cmd)
+}
+
+func (m *mongoMonitor) succeeded(_ context.Context, e *event.CommandSucceededEvent) {
+	m.observe(e.RequestID, e.CommandName, e.Duration, "ok")
+}
+
+func (m *mongoMonitor) failed(_ context.Context, e *event.CommandFailedEvent) {
+	m.observe(e.RequestID, e.CommandName, e.Duration, "error")
+}
+
+func (m *mongoMonitor) observe(requestID int64, name string, elapsed time.Duration, outcome string) {
+	cmd := mongoCommand{command: name}
+	if v, ok := m.inflight.LoadAndDelete(requestID); ok {
+		cmd = v.(mongoCommand)
+	}
+	mongoDuration.WithLabelValues(cmd.collection, cmd.command, outcome).Observe(elapsed.Seconds())
+}
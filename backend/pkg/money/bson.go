@@ -0,0 +1,45 @@
+package money
+
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+type bsonMoney struct {
+	Amount   int64  `bson:"amount"`
+	Currency string `bson:"currency"`
+}
+
+// MarshalBSONValue stores Money as {amount: <int64 minor units>, currency}.
+// Queries and sorts on the amount use the "<field>.amount" path.
+func (m Money) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bson.MarshalValue(bsonMoney{Amount: m.amount, Currency: m.currency})
+}
+
+// UnmarshalBSONValue reads the form MarshalBSONValue writes, and also bare
+// numbers stored before Money existed, which are taken to be LegacyCurrency.
+func (m *Money) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	value := bsoncore.Value{Type: t, Data: data}
+	switch t {
+	case bsontype.EmbeddedDocument:
+		var v bsonMoney
+		if err := bson.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		*m = Money{amount: v.Amount, currency: v.Currency}
+	case bsontype.Double:
+		*m = FromFloat(value.Double(), LegacyCurrency)
+	case bsontype.Int32:
+		*m = New(int64(value.Int32())*100, LegacyCurrency)
+	case bsontype.Int64:
+		*m = New(value.Int64()*100, LegacyCurrency)
+	case bsontype.Null, bsontype.Undefined:
+		*m = Money{}
+	default:
+		return fmt.Errorf("money: cannot decode BSON %s", t)
+	}
+	return nil
+}
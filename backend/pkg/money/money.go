@@ -0,0 +1,217 @@
+// Package money provides Money, an exact amount of an ISO 4217 currency, so
+// prices and totals are never rounded through float64.
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// LegacyCurrency is the currency assumed for amounts stored as bare numbers
+// before Money existed. Every service defaulted to it at the time.
+const LegacyCurrency = "USD"
+
+var (
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrInvalidAmount    = errors.New("invalid amount")
+	ErrInvalidCurrency  = errors.New("invalid currency")
+)
+
+// Money is an amount in the minor units of a currency, e.g. cents for USD.
+// The zero value is zero of no currency; it can be added to any Money, which
+// makes it a convenient starting point for sums.
+type Money struct {
+	amount   int64
+	currency string
+}
+
+// New returns amount minor units of currency.
+func New(amount int64, currency string) Money {
+	return Money{amount: amount, currency: currency}
+}
+
+func Zero(currency string) Money {
+	return Money{currency: currency}
+}
+
+// Parse reads a decimal amount such as "12.50" or "-3" in currency. It
+// rejects amounts with more decimal places than the currency has.
+func Parse(amount, currency string) (Money, error) {
+	currency = strings.ToUpper(currency)
+	if !IsCurrency(currency) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidCurrency, currency)
+	}
+
+	digits := MinorUnits(currency)
+	whole, fraction, hasPoint := strings.Cut(amount, ".")
+	negative := strings.HasPrefix(whole, "-")
+	whole = strings.TrimPrefix(whole, "-")
+	if whole == "" || !isDigits(whole) || (hasPoint && (fraction == "" || !isDigits(fraction))) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+	if len(fraction) > digits {
+		return Money{}, fmt.Errorf("%w: %q has more than %d decimal places for %s", ErrInvalidAmount, amount, digits, currency)
+	}
+
+	minor, err := strconv.ParseInt(whole+fraction+strings.Repeat("0", digits-len(fraction)), 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+	if negative {
+		minor = -minor
+	}
+	return Money{amount: minor, currency: currency}, nil
+}
+
+// FromFloat converts a float amount, rounding half away from zero to the
+// currency's minor unit. It exists for reading data stored before Money and
+// should not be used for new amounts.
+func FromFloat(value float64, currency string) Money {
+	scale := math.Pow10(MinorUnits(currency))
+	return Money{amount: int64(math.Round(value * scale)), currency: currency}
+}
+
+// Amount returns the amount in minor units.
+func (m Money) Amount() int64 {
+	return m.amount
+}
+
+func (m Money) Currency() string {
+	return m.currency
+}
+
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.amount < 0
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	currency, err := m.common(other)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{amount: m.amount + other.amount, currency: currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	currency, err := m.common(other)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{amount: m.amount - other.amount, currency: currency}, nil
+}
+
+// Mul returns m times n, e.g. a unit price times a quantity.
+func (m Money) Mul(n int64) Money {
+	return Money{amount: m.amount * n, currency: m.currency}
+}
+
+// Cmp returns -1, 0 or +1 as m is less than, equal to or greater than other.
+func (m Money) Cmp(other Money) (int, error) {
+	if _, err := m.common(other); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.amount < other.amount:
+		return -1, nil
+	case m.amount > other.amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// common returns the currency m and other share. A zero value of no currency
+// takes on the other's currency.
+func (m Money) common(other Money) (string, error) {
+	switch {
+	case m.currency == other.currency:
+		return m.currency, nil
+	case m.currency == "" && m.amount == 0:
+		return other.currency, nil
+	case other.currency == "" && other.amount == 0:
+		return m.currency, nil
+	}
+	return "", fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currency, other.currency)
+}
+
+// Decimal formats the amount with the currency's decimal places, e.g. "12.50".
+func (m Money) Decimal() string {
+	digits := MinorUnits(m.currency)
+	sign := ""
+	amount := m.amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	s := strconv.FormatInt(amount, 10)
+	if digits == 0 {
+		return sign + s
+	}
+	if len(s) <= digits {
+		s = strings.Repeat("0", digits-len(s)+1) + s
+	}
+	return sign + s[:len(s)-digits] + "." + s[len(s)-digits:]
+}
+
+// Float64 returns the amount in major units. It is lossy and meant for
+// metrics and display only.
+func (m Money) Float64() float64 {
+	return float64(m.amount) / math.Pow10(MinorUnits(m.currency))
+}
+
+func (m Money) String() string {
+	return strings.TrimSpace(m.Decimal() + " " + m.currency)
+}
+
+type jsonMoney struct {
+	Amount   json.Number `json:"amount"`
+	Currency string      `json:"currency"`
+}
+
+// MarshalJSON writes {"amount":"12.50","currency":"USD"}. The amount is a
+// string so clients never parse it into a float by accident.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{m.Decimal(), m.currency})
+}
+
+// UnmarshalJSON reads the form MarshalJSON writes. The amount may also be a
+// JSON number. A zero amount without a currency decodes to the zero Money.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var v jsonMoney
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Currency == "" && isZeroAmount(v.Amount.String()) {
+		*m = Money{}
+		return nil
+	}
+	parsed, err := Parse(v.Amount.String(), v.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+func isZeroAmount(s string) bool {
+	return strings.Trim(s, "0.") == ""
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
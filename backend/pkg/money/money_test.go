@@ -0,0 +1,141 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestParse(t *testing.T) {
+	for input, expected := range map[[2]string]Money{
+		{"12.50", "USD"}: New(1250, "USD"),
+		{"12.5", "usd"}:  New(1250, "USD"),
+		{"-3", "EUR"}:    New(-300, "EUR"),
+		{"0.07", "GBP"}:  New(7, "GBP"),
+		{"1500", "JPY"}:  New(1500, "JPY"),
+		{"1.234", "KWD"}: New(1234, "KWD"),
+	} {
+		m, err := Parse(input[0], input[1])
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, m, input)
+	}
+
+	for _, input := range [][2]string{
+		{"12.505", "USD"},
+		{"1.5", "JPY"},
+		{"abc", "USD"},
+		{"1.", "USD"},
+		{"", "USD"},
+		{"1e3", "USD"},
+	} {
+		_, err := Parse(input[0], input[1])
+		assert.ErrorIs(t, err, ErrInvalidAmount, input)
+	}
+
+	_, err := Parse("1.00", "XXY")
+	assert.ErrorIs(t, err, ErrInvalidCurrency)
+}
+
+func TestArithmetic(t *testing.T) {
+	a := New(1050, "USD")
+	b := New(295, "USD")
+
+	sum, err := a.Add(b)
+	assert.NoError(t, err)
+	assert.Equal(t, New(1345, "USD"), sum)
+
+	diff, err := b.Sub(a)
+	assert.NoError(t, err)
+	assert.Equal(t, New(-755, "USD"), diff)
+	assert.True(t, diff.IsNegative())
+
+	assert.Equal(t, New(3150, "USD"), a.Mul(3))
+
+	cmp, err := a.Cmp(b)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, cmp)
+
+	_, err = a.Add(New(100, "EUR"))
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	total, err := Money{}.Add(a)
+	assert.NoError(t, err)
+	assert.Equal(t, a, total)
+
+	// The float sum of these is 0.30000000000000004.
+	exact, _ := New(10, "USD").Add(New(20, "USD"))
+	assert.Equal(t, "0.30", exact.Decimal())
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "12.50", New(1250, "USD").Decimal())
+	assert.Equal(t, "0.05", New(5, "USD").Decimal())
+	assert.Equal(t, "-0.05", New(-5, "USD").Decimal())
+	assert.Equal(t, "1500", New(1500, "JPY").Decimal())
+	assert.Equal(t, "1.234", New(1234, "KWD").Decimal())
+	assert.Equal(t, "12.50 USD", New(1250, "USD").String())
+	assert.Equal(t, 12.5, New(1250, "USD").Float64())
+}
+
+func TestFromFloat(t *testing.T) {
+	assert.Equal(t, New(1999, "USD"), FromFloat(19.99, "USD"))
+	assert.Equal(t, New(30, "USD"), FromFloat(0.1+0.2, "USD"))
+	assert.Equal(t, New(-250, "USD"), FromFloat(-2.5, "USD"))
+	assert.Equal(t, New(1500, "JPY"), FromFloat(1500, "JPY"))
+}
+
+func TestJSON(t *testing.T) {
+	data, err := json.Marshal(New(1250, "USD"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"amount":"12.50","currency":"USD"}`, string(data))
+
+	var m Money
+	assert.NoError(t, json.Unmarshal(data, &m))
+	assert.Equal(t, New(1250, "USD"), m)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount":12.5,"currency":"EUR"}`), &m))
+	assert.Equal(t, New(1250, "EUR"), m)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"amount":"12.50"}`), &m))
+	assert.Error(t, json.Unmarshal([]byte(`12.5`), &m))
+
+	data, err = json.Marshal(Money{})
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(data, &m))
+	assert.Equal(t, Money{}, m)
+}
+
+func TestBSON(t *testing.T) {
+	type doc struct {
+		Price Money `bson:"price"`
+	}
+
+	data, err := bson.Marshal(doc{Price: New(1250, "THB")})
+	assert.NoError(t, err)
+
+	var raw bson.M
+	assert.NoError(t, bson.Unmarshal(data, &raw))
+	assert.Equal(t, bson.M{"amount": int64(1250), "currency": "THB"}, raw["price"])
+
+	var decoded doc
+	assert.NoError(t, bson.Unmarshal(data, &decoded))
+	assert.Equal(t, New(1250, "THB"), decoded.Price)
+
+	t.Run("Legacy Numbers", func(t *testing.T) {
+		for stored, expected := range map[interface{}]Money{
+			19.99:     New(1999, LegacyCurrency),
+			int32(5):  New(500, LegacyCurrency),
+			int64(7):  New(700, LegacyCurrency),
+			0.1 + 0.2: New(30, LegacyCurrency),
+		} {
+			data, err := bson.Marshal(bson.M{"price": stored})
+			assert.NoError(t, err)
+
+			var decoded doc
+			assert.NoError(t, bson.Unmarshal(data, &decoded))
+			assert.Equal(t, expected, decoded.Price, stored)
+		}
+	})
+}
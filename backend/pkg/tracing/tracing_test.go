@@ -0,0 +1,68 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestSetup(t *testing.T) {
+	t.Run("Propagates W3C Trace Context", func(t *testing.T) {
+		t.Setenv("OTEL_TRACES_EXPORTER", ExporterNone)
+
+		shutdown, err := Setup(context.Background(), "test-service")
+		require.NoError(t, err)
+		defer shutdown(context.Background())
+
+		ctx, span := otel.Tracer("test").Start(context.Background(), "op")
+		defer span.End()
+		carrier := propagation.MapCarrier{}
+		otel.GetTextMapPropagator().Inject(ctx, carrier)
+
+		assert.Contains(t, carrier["traceparent"], span.SpanContext().TraceID().String())
+	})
+
+	t.Run("Console Exporter", func(t *testing.T) {
+		t.Setenv("OTEL_TRACES_EXPORTER", ExporterConsole)
+
+		shutdown, err := Setup(context.Background(), "test-service")
+
+		require.NoError(t, err)
+		assert.NoError(t, shutdown(context.Background()))
+	})
+
+	t.Run("Unknown Exporter", func(t *testing.T) {
+		t.Setenv("OTEL_TRACES_EXPORTER", "zipkin")
+
+		_, err := Setup(context.Background(), "test-service")
+
+		assert.ErrorContains(t, err, "zipkin")
+	})
+}
+
+func TestMongoMonitor(t *testing.T) {
+	var started, succeeded int
+	monitor := MongoMonitor(&event.CommandMonitor{
+		Started:   func(context.Context, *event.CommandStartedEvent) { started++ },
+		Succeeded: func(context.Context, *event.CommandSucceededEvent) { succeeded++ },
+	})
+	command, err := bson.Marshal(bson.D{{Key: "find", Value: "orders"}})
+	require.NoError(t, err)
+
+	monitor.Started(context.Background(), &event.CommandStartedEvent{Command: command, CommandName: "find", RequestID: 1})
+	monitor.Succeeded(context.Background(), &event.CommandSucceededEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{RequestID: 1, CommandName: "find"},
+	})
+	monitor.Failed(context.Background(), &event.CommandFailedEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{RequestID: 2, CommandName: "insert"},
+	})
+
+	assert.Equal(t, 1, started)
+	assert.Equal(t, 1, succeeded)
+}
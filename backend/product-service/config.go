@@ -0,0 +1,22 @@
+package main
+
+import (
+	"time"
+
+	"github.com/yourusername/ecommerce/pkg/config"
+)
+
+// Config holds the product-service settings; see config.Load for the sources.
+type Config struct {
+	HTTP  config.HTTP  `yaml:"http"`
+	Mongo config.Mongo `yaml:"mongo"`
+	Log   config.Log   `yaml:"log"`
+
+	ProductsCollection string `yaml:"products_collection" env:"MONGODB_PRODUCTS_COLLECTION" flag:"products-collection" default:"products" required:"true" usage:"collection holding the products"`
+
+	// Verifies the admin and service tokens the inventory endpoints require.
+	JWTSecret string `yaml:"jwt_secret" env:"JWT_SECRET" flag:"jwt-secret" required:"true" secret:"true" usage:"HS256 secret shared with auth-service and order-service"`
+
+	ShutdownDelay   time.Duration `yaml:"shutdown_delay" env:"SHUTDOWN_DELAY" flag:"shutdown-delay" default:"5s" usage:"how long /readyz fails before the listener closes"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" flag:"shutdown-timeout" default:"20s" usage:"how long in-flight requests may take to finish"`
+}
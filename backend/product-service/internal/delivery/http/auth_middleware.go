@@ -0,0 +1,70 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Roles allowed to change stock and reservations: admins, and services such
+// as order-service acting for an order.
+const (
+	RoleAdmin   = "admin"
+	RoleService = "service"
+)
+
+type tokenClaims struct {
+	Role  string   `json:"role"`
+	Roles []string `json:"roles"`
+	jwt.RegisteredClaims
+}
+
+func (c *tokenClaims) hasRole(roles []string) bool {
+	for _, want := range roles {
+		if c.Role == want {
+			return true
+		}
+		for _, role := range c.Roles {
+			if role == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RequireRole admits requests with an unexpired HS256 bearer token signed
+// with secret, as auth-service and order-service issue them, that carries
+// one of roles. Requests without a valid token get 401, and valid tokens
+// without the role 403.
+func RequireRole(secret string, roles ...string) gin.HandlerFunc {
+	keyFunc := func(*jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	}
+	return func(c *gin.Context) {
+		tokenString, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || tokenString == "" {
+			c.Header("WWW-Authenticate", "Bearer")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "a bearer token is required"})
+			return
+		}
+
+		var claims tokenClaims
+		_, err := jwt.ParseWithClaims(tokenString, &claims, keyFunc,
+			jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+			jwt.WithExpirationRequired(),
+		)
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "the bearer token is invalid or expired"})
+			return
+		}
+		if !claims.hasRole(roles) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "the bearer token does not allow this operation"})
+			return
+		}
+		c.Next()
+	}
+}
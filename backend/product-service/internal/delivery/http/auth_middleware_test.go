@@ -0,0 +1,55 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PUT("/api/v1/products/:id/stock", RequireRole("secret", RoleAdmin, RoleService), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	sign := func(secret string, claims tokenClaims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+		assert.NoError(t, err)
+		return token
+	}
+	expiresAt := jwt.NewNumericDate(time.Now().Add(time.Minute))
+
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"Service", "Bearer " + sign("secret", tokenClaims{Role: RoleService, RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: expiresAt}}), http.StatusOK},
+		{"Admin In Roles", "Bearer " + sign("secret", tokenClaims{Roles: []string{"user", RoleAdmin}, RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: expiresAt}}), http.StatusOK},
+		{"User", "Bearer " + sign("secret", tokenClaims{Role: "user", RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: expiresAt}}), http.StatusForbidden},
+		{"No Token", "", http.StatusUnauthorized},
+		{"Wrong Secret", "Bearer " + sign("other", tokenClaims{Role: RoleAdmin, RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: expiresAt}}), http.StatusUnauthorized},
+		{"Expired", "Bearer " + sign("secret", tokenClaims{Role: RoleAdmin, RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute))}}), http.StatusUnauthorized},
+		{"Without Expiry", "Bearer " + sign("secret", tokenClaims{Role: RoleAdmin}), http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("PUT", "/api/v1/products/507f1f77bcf86cd799439011/stock", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.want, rr.Code)
+		})
+	}
+}
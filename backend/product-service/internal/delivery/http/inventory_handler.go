@@ -0,0 +1,180 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/ecommerce/product-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type InventoryHandler struct {
+	inventoryUseCase domain.InventoryUseCase
+}
+
+type stockRequest struct {
+	OnHand *int `json:"on_hand" binding:"required,gte=0"`
+}
+
+type stockResponse struct {
+	*domain.Stock
+	Available int `json:"available"`
+}
+
+type reservationItemRequest struct {
+	ProductID string `json:"product_id" binding:"required,len=24,hexadecimal"`
+	Quantity  int    `json:"quantity" binding:"required,gte=1"`
+}
+
+type reservationRequest struct {
+	OrderID    string                   `json:"order_id" binding:"required"`
+	Items      []reservationItemRequest `json:"items" binding:"required,min=1,dive"`
+	TTLSeconds int                      `json:"ttl_seconds" binding:"omitempty,gte=1"`
+}
+
+func (req reservationRequest) toItems() []domain.ReservationItem {
+	items := make([]domain.ReservationItem, len(req.Items))
+	for i, item := range req.Items {
+		// Binding has already checked the ID is 24 hex characters.
+		productID, _ := primitive.ObjectIDFromHex(item.ProductID)
+		items[i] = domain.ReservationItem{ProductID: productID, Quantity: item.Quantity}
+	}
+	return items
+}
+
+// NewInventoryHandler registers the inventory routes. Stock levels can be read
+// by anyone; setting them and every reservation route go through auth first.
+func NewInventoryHandler(r gin.IRouter, inventoryUseCase domain.InventoryUseCase, auth gin.HandlerFunc) {
+	handler := &InventoryHandler{
+		inventoryUseCase: inventoryUseCase,
+	}
+
+	r.GET("/api/v1/products/:id/stock", handler.GetStock)
+
+	restricted := r.Group("", auth)
+	restricted.PUT("/api/v1/products/:id/stock", handler.SetStock)
+	restricted.POST("/api/v1/reservations", handler.Reserve)
+	restricted.GET("/api/v1/reservations/:order_id", handler.GetReservation)
+	restricted.POST("/api/v1/reservations/:order_id/commit", handler.Commit)
+	restricted.POST("/api/v1/reservations/:order_id/reopen", handler.Reopen)
+	restricted.POST("/api/v1/reservations/:order_id/release", handler.Release)
+}
+
+func (h *InventoryHandler) GetStock(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	stock, err := h.inventoryUseCase.GetStock(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(statusForError(err), inventoryError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, stockResponse{Stock: stock, Available: stock.Available()})
+}
+
+func (h *InventoryHandler) SetStock(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req stockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stock, err := h.inventoryUseCase.SetStock(c.Request.Context(), id, *req.OnHand)
+	if err != nil {
+		c.JSON(statusForError(err), inventoryError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, stockResponse{Stock: stock, Available: stock.Available()})
+}
+
+func (h *InventoryHandler) Reserve(c *gin.Context) {
+	var req reservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ttl := time.Duration(req.TTLSeconds) * time.Second
+	reservation, err := h.inventoryUseCase.Reserve(c.Request.Context(), req.OrderID, req.toItems(), ttl)
+	if err != nil {
+		c.JSON(statusForError(err), inventoryError(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, reservation)
+}
+
+func (h *InventoryHandler) GetReservation(c *gin.Context) {
+	reservation, err := h.inventoryUseCase.GetReservation(c.Request.Context(), c.Param("order_id"))
+	if err != nil {
+		c.JSON(statusForError(err), inventoryError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+func (h *InventoryHandler) Commit(c *gin.Context) {
+	reservation, err := h.inventoryUseCase.Commit(c.Request.Context(), c.Param("order_id"))
+	if err != nil {
+		c.JSON(statusForError(err), inventoryError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+func (h *InventoryHandler) Reopen(c *gin.Context) {
+	reservation, err := h.inventoryUseCase.Reopen(c.Request.Context(), c.Param("order_id"))
+	if err != nil {
+		c.JSON(statusForError(err), inventoryError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+func (h *InventoryHandler) Release(c *gin.Context) {
+	reservation, err := h.inventoryUseCase.Release(c.Request.Context(), c.Param("order_id"))
+	if err != nil {
+		c.JSON(statusForError(err), inventoryError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+// inventoryError is the error body of the inventory routes. Besides the
+// message it carries a stable code, so callers such as order-service can tell
+// apart errors that share a status, like the two 409s.
+func inventoryError(err error) gin.H {
+	body := gin.H{"error": err.Error()}
+	switch {
+	case errors.Is(err, domain.ErrInsufficientStock):
+		body["code"] = "insufficient_stock"
+	case errors.Is(err, domain.ErrReservationClosed):
+		body["code"] = "reservation_closed"
+	case errors.Is(err, domain.ErrReservationNotFound):
+		body["code"] = "reservation_not_found"
+	case errors.Is(err, domain.ErrProductNotFound):
+		body["code"] = "product_not_found"
+	case errors.Is(err, domain.ErrInvalidReservation):
+		body["code"] = "invalid_reservation"
+	case errors.Is(err, domain.ErrInvalidStock):
+		body["code"] = "invalid_stock"
+	}
+	return body
+}
@@ -0,0 +1,220 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/ecommerce/product-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MockInventoryUseCase struct {
+	mock.Mock
+}
+
+func (m *MockInventoryUseCase) GetStock(ctx context.Context, productID primitive.ObjectID) (*domain.Stock, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Stock), args.Error(1)
+}
+
+func (m *MockInventoryUseCase) SetStock(ctx context.Context, productID primitive.ObjectID, onHand int) (*domain.Stock, error) {
+	args := m.Called(ctx, productID, onHand)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Stock), args.Error(1)
+}
+
+func (m *MockInventoryUseCase) GetReservation(ctx context.Context, orderID string) (*domain.Reservation, error) {
+	return m.reservation(m.Called(ctx, orderID))
+}
+
+func (m *MockInventoryUseCase) Reserve(ctx context.Context, orderID string, items []domain.ReservationItem, ttl time.Duration) (*domain.Reservation, error) {
+	return m.reservation(m.Called(ctx, orderID, items, ttl))
+}
+
+func (m *MockInventoryUseCase) Commit(ctx context.Context, orderID string) (*domain.Reservation, error) {
+	return m.reservation(m.Called(ctx, orderID))
+}
+
+func (m *MockInventoryUseCase) Reopen(ctx context.Context, orderID string) (*domain.Reservation, error) {
+	return m.reservation(m.Called(ctx, orderID))
+}
+
+func (m *MockInventoryUseCase) Release(ctx context.Context, orderID string) (*domain.Reservation, error) {
+	return m.reservation(m.Called(ctx, orderID))
+}
+
+func (m *MockInventoryUseCase) ExpireReservations(ctx context.Context, now time.Time) (int, error) {
+	args := m.Called(ctx, now)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockInventoryUseCase) reservation(args mock.Arguments) (*domain.Reservation, error) {
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Reservation), args.Error(1)
+}
+
+func newInventoryTestRouter(useCase domain.InventoryUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewInventoryHandler(router, useCase, func(c *gin.Context) { c.Next() })
+	return router
+}
+
+func TestStock(t *testing.T) {
+	mockUseCase := new(MockInventoryUseCase)
+	router := newInventoryTestRouter(mockUseCase)
+	id := primitive.NewObjectID()
+
+	t.Run("Get", func(t *testing.T) {
+		mockUseCase.On("GetStock", mock.Anything, id).Return(&domain.Stock{ProductID: id, OnHand: 10, Reserved: 3}, nil).Once()
+
+		req := httptest.NewRequest("GET", "/api/v1/products/"+id.Hex()+"/stock", nil)
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var response map[string]interface{}
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, 10.0, response["on_hand"])
+		assert.Equal(t, 7.0, response["available"])
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Set", func(t *testing.T) {
+		mockUseCase.On("SetStock", mock.Anything, id, 25).Return(&domain.Stock{ProductID: id, OnHand: 25}, nil).Once()
+
+		req := httptest.NewRequest("PUT", "/api/v1/products/"+id.Hex()+"/stock", bytes.NewBufferString(`{"on_hand":25}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Set Negative", func(t *testing.T) {
+		req := httptest.NewRequest("PUT", "/api/v1/products/"+id.Hex()+"/stock", bytes.NewBufferString(`{"on_hand":-1}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestReservations(t *testing.T) {
+	mockUseCase := new(MockInventoryUseCase)
+	router := newInventoryTestRouter(mockUseCase)
+	productID := primitive.NewObjectID()
+
+	t.Run("Reserve", func(t *testing.T) {
+		items := []domain.ReservationItem{{ProductID: productID, Quantity: 2}}
+		mockUseCase.On("Reserve", mock.Anything, "order-1", items, 5*time.Minute).
+			Return(&domain.Reservation{OrderID: "order-1", Items: items, Status: domain.ReservationReserved}, nil).Once()
+
+		body := `{"order_id":"order-1","items":[{"product_id":"` + productID.Hex() + `","quantity":2}],"ttl_seconds":300}`
+		req := httptest.NewRequest("POST", "/api/v1/reservations", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Insufficient Stock", func(t *testing.T) {
+		mockUseCase.On("Reserve", mock.Anything, "order-2", mock.Anything, time.Duration(0)).Return(nil, domain.ErrInsufficientStock).Once()
+
+		body := `{"order_id":"order-2","items":[{"product_id":"` + productID.Hex() + `","quantity":2}]}`
+		req := httptest.NewRequest("POST", "/api/v1/reservations", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		assert.JSONEq(t, `{"error":"`+domain.ErrInsufficientStock.Error()+`","code":"insufficient_stock"}`, rr.Body.String())
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Product ID", func(t *testing.T) {
+		body := `{"order_id":"order-3","items":[{"product_id":"abc","quantity":2}]}`
+		req := httptest.NewRequest("POST", "/api/v1/reservations", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Commit", func(t *testing.T) {
+		mockUseCase.On("Commit", mock.Anything, "order-1").
+			Return(&domain.Reservation{OrderID: "order-1", Status: domain.ReservationCommitted}, nil).Once()
+
+		req := httptest.NewRequest("POST", "/api/v1/reservations/order-1/commit", nil)
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Reopen", func(t *testing.T) {
+		mockUseCase.On("Reopen", mock.Anything, "order-1").
+			Return(&domain.Reservation{OrderID: "order-1", Status: domain.ReservationReserved}, nil).Once()
+
+		req := httptest.NewRequest("POST", "/api/v1/reservations/order-1/reopen", nil)
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Release Committed", func(t *testing.T) {
+		mockUseCase.On("Release", mock.Anything, "order-1").Return(nil, domain.ErrReservationClosed).Once()
+
+		req := httptest.NewRequest("POST", "/api/v1/reservations/order-1/release", nil)
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		assert.JSONEq(t, `{"error":"`+domain.ErrReservationClosed.Error()+`","code":"reservation_closed"}`, rr.Body.String())
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Reservation", func(t *testing.T) {
+		mockUseCase.On("GetReservation", mock.Anything, "order-9").Return(nil, domain.ErrReservationNotFound).Once()
+
+		req := httptest.NewRequest("GET", "/api/v1/reservations/order-9", nil)
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+}
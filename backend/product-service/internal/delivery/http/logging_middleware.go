@@ -0,0 +1,26 @@
+package http
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/ecommerce/pkg/logging"
+)
+
+// LoggingMiddleware takes the request ID from X-Request-ID (or makes one up),
+// echoes it in the response and puts it in the request context so every log
+// line of the request carries it. With accessLog it also logs each request
+// once it finished.
+func LoggingMiddleware(accessLog bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := logging.RequestIDFromHeader(c.Request.Header)
+		c.Header(logging.HeaderRequestID, id)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+
+		start := time.Now()
+		c.Next()
+		if accessLog {
+			logging.Access(c.Request.Context(), c.Request.Method, c.Request.URL.Path, c.FullPath(), c.Writer.Status(), time.Since(start))
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/ecommerce/pkg/logging"
+)
+
+func TestLoggingMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	handler, err := logging.NewHandler(&buf, "info", logging.FormatJSON)
+	require.NoError(t, err)
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(handler))
+
+	gin.SetMode(gin.TestMode)
+	var seen string
+	router := gin.New()
+	router.Use(LoggingMiddleware(true))
+	router.GET("/api/v1/products/:id", func(c *gin.Context) {
+		seen = logging.RequestID(c.Request.Context())
+		c.Status(http.StatusTeapot)
+	})
+
+	req := httptest.NewRequest("GET", "/api/v1/products/507f1f77bcf86cd799439011", nil)
+	req.Header.Set(logging.HeaderRequestID, "kong-1")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, "kong-1", seen)
+	assert.Equal(t, "kong-1", rr.Header().Get(logging.HeaderRequestID))
+
+	var line map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "kong-1", line["request_id"])
+	assert.Equal(t, "/api/v1/products/:id", line["route"])
+	assert.Equal(t, float64(http.StatusTeapot), line["status"])
+}
@@ -0,0 +1,19 @@
+package http
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/ecommerce/pkg/metrics"
+)
+
+// MetricsMiddleware records request count, status and latency per route
+// template (c.FullPath), so /api/v1/products/:id is one series however many
+// products exist.
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		metrics.ObserveHTTP(c.Request.Method, c.FullPath(), c.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,32 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/ecommerce/pkg/metrics"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(MetricsMiddleware())
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	router.GET("/api/v1/products/:id", func(c *gin.Context) {
+		c.Status(http.StatusTeapot)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v1/products/507f1f77bcf86cd799439011", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/no/such/route", nil))
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `http_requests_total{method="GET",route="/api/v1/products/:id",status="418"} 1`)
+	assert.Contains(t, rr.Body.String(), `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.NotContains(t, rr.Body.String(), "507f1f77bcf86cd799439011")
+}
@@ -0,0 +1,193 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/ecommerce/pkg/money"
+	"github.com/yourusername/ecommerce/product-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ProductHandler struct {
+	productUseCase domain.ProductUseCase
+}
+
+type productRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	// Price is sent as products are returned, {"amount": "19.99",
+	// "currency": "USD"}, or as a bare decimal amount (a string or a number)
+	// in Currency.
+	Price    json.RawMessage `json:"price" binding:"required"`
+	SKU      string          `json:"sku"`
+	Currency string          `json:"currency" binding:"omitempty,len=3,alpha"`
+	Active   *bool           `json:"active"`
+}
+
+func (req productRequest) price() (money.Money, error) {
+	if raw := bytes.TrimSpace(req.Price); len(raw) > 0 && raw[0] == '{' {
+		var price money.Money
+		if err := json.Unmarshal(raw, &price); err != nil || price.Currency() == "" {
+			return money.Money{}, domain.ErrInvalidPrice
+		}
+		if req.Currency != "" && !strings.EqualFold(req.Currency, price.Currency()) {
+			return money.Money{}, domain.ErrInvalidPrice
+		}
+		return price, nil
+	}
+
+	var amount json.Number
+	if err := json.Unmarshal(req.Price, &amount); err != nil {
+		return money.Money{}, domain.ErrInvalidPrice
+	}
+	currency := req.Currency
+	if currency == "" {
+		currency = domain.DefaultCurrency
+	}
+	price, err := money.Parse(amount.String(), currency)
+	if err != nil {
+		return money.Money{}, domain.ErrInvalidPrice
+	}
+	return price, nil
+}
+
+func (req productRequest) toProduct() (domain.Product, error) {
+	price, err := req.price()
+	if err != nil {
+		return domain.Product{}, err
+	}
+
+	product := domain.Product{
+		Name:        req.Name,
+		Description: req.Description,
+		Price:       price,
+		SKU:         req.SKU,
+		Active:      true,
+	}
+	if req.Active != nil {
+		product.Active = *req.Active
+	}
+	return product, nil
+}
+
+func NewProductHandler(r gin.IRouter, productUseCase domain.ProductUseCase) {
+	handler := &ProductHandler{
+		productUseCase: productUseCase,
+	}
+
+	r.POST("/api/v1/products", handler.CreateProduct)
+	r.GET("/api/v1/products", handler.GetProducts)
+	r.GET("/api/v1/products/:id", handler.GetProduct)
+	r.PUT("/api/v1/products/:id", handler.UpdateProduct)
+	r.DELETE("/api/v1/products/:id", handler.DeleteProduct)
+}
+
+func (h *ProductHandler) CreateProduct(c *gin.Context) {
+	var req productRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	product, err := req.toProduct()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.productUseCase.CreateProduct(c.Request.Context(), &product); err != nil {
+		c.JSON(statusForError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, product)
+}
+
+func (h *ProductHandler) GetProducts(c *gin.Context) {
+	products, err := h.productUseCase.GetProducts(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, products)
+}
+
+func (h *ProductHandler) GetProduct(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	product, err := h.productUseCase.GetProduct(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if product == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
+func (h *ProductHandler) UpdateProduct(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req productRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	product, err := req.toProduct()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	product.ID = id
+	if err := h.productUseCase.UpdateProduct(c.Request.Context(), &product); err != nil {
+		c.JSON(statusForError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Product updated successfully"})
+}
+
+func (h *ProductHandler) DeleteProduct(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	if err := h.productUseCase.DeleteProduct(c.Request.Context(), id); err != nil {
+		c.JSON(statusForError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Product deleted successfully"})
+}
+
+func statusForError(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrProductNotFound), errors.Is(err, domain.ErrReservationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInsufficientStock), errors.Is(err, domain.ErrReservationClosed):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrInvalidReservation), errors.Is(err, domain.ErrInvalidStock), errors.Is(err, domain.ErrInvalidPrice):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
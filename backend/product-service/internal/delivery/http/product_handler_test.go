@@ -0,0 +1,275 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/ecommerce/pkg/money"
+	"github.com/yourusername/ecommerce/product-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MockProductUseCase struct {
+	mock.Mock
+}
+
+func (m *MockProductUseCase) CreateProduct(ctx context.Context, product *domain.Product) error {
+	args := m.Called(ctx, product)
+	return args.Error(0)
+}
+
+func (m *MockProductUseCase) GetProduct(ctx context.Context, id primitive.ObjectID) (*domain.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Product), args.Error(1)
+}
+
+func (m *MockProductUseCase) GetProducts(ctx context.Context) ([]domain.Product, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.Product), args.Error(1)
+}
+
+func (m *MockProductUseCase) UpdateProduct(ctx context.Context, product *domain.Product) error {
+	args := m.Called(ctx, product)
+	return args.Error(0)
+}
+
+func (m *MockProductUseCase) DeleteProduct(ctx context.Context, id primitive.ObjectID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func newTestRouter(useCase domain.ProductUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewProductHandler(router, useCase)
+	return router
+}
+
+func TestCreateProduct(t *testing.T) {
+	mockUseCase := new(MockProductUseCase)
+	router := newTestRouter(mockUseCase)
+
+	t.Run("Success", func(t *testing.T) {
+		mockUseCase.On("CreateProduct", mock.Anything, mock.MatchedBy(func(p *domain.Product) bool {
+			return p.Name == "Test Product" && p.Price == money.New(9999, domain.DefaultCurrency) && p.Active
+		})).Return(nil).Once()
+
+		body := `{"name":"Test Product","price":99.99,"description":"This is a test product"}`
+		req := httptest.NewRequest("POST", "/api/v1/products", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Missing Price", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/v1/products", bytes.NewBufferString(`{"name":"Test Product"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Price With Currency", func(t *testing.T) {
+		mockUseCase.On("CreateProduct", mock.Anything, mock.MatchedBy(func(p *domain.Product) bool {
+			return p.Price == money.New(1500, "JPY")
+		})).Return(nil).Once()
+
+		req := httptest.NewRequest("POST", "/api/v1/products", bytes.NewBufferString(`{"name":"Tea","price":"1500","currency":"jpy"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"price":{"amount":"1500","currency":"JPY"}`)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Price As Returned", func(t *testing.T) {
+		mockUseCase.On("CreateProduct", mock.Anything, mock.MatchedBy(func(p *domain.Product) bool {
+			return p.Price == money.New(1250, "EUR")
+		})).Return(nil).Once()
+
+		req := httptest.NewRequest("POST", "/api/v1/products", bytes.NewBufferString(`{"name":"Tea","price":{"amount":"12.50","currency":"EUR"}}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"price":{"amount":"12.50","currency":"EUR"}`)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Conflicting Currencies", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/v1/products", bytes.NewBufferString(`{"name":"Tea","price":{"amount":"12.50","currency":"EUR"},"currency":"USD"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Too Many Decimals", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/v1/products", bytes.NewBufferString(`{"name":"Test Product","price":"1.999"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Negative Price", func(t *testing.T) {
+		mockUseCase.On("CreateProduct", mock.Anything, mock.Anything).Return(domain.ErrInvalidPrice).Once()
+
+		req := httptest.NewRequest("POST", "/api/v1/products", bytes.NewBufferString(`{"name":"Test Product","price":-1}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestGetProduct(t *testing.T) {
+	mockUseCase := new(MockProductUseCase)
+	router := newTestRouter(mockUseCase)
+
+	t.Run("Success", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		expected := &domain.Product{ID: id, Name: "Keyboard", Price: money.New(50000, "USD"), SKU: "SKU-456", Active: true}
+		mockUseCase.On("GetProduct", mock.Anything, id).Return(expected, nil).Once()
+
+		req := httptest.NewRequest("GET", "/api/v1/products/"+id.Hex(), nil)
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var response domain.Product
+		err := json.Unmarshal(rr.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, expected.ID, response.ID)
+		assert.Equal(t, expected.SKU, response.SKU)
+		assert.Equal(t, expected.Price, response.Price)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Invalid ID", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/products/invalid-id", nil)
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockUseCase.On("GetProduct", mock.Anything, id).Return(nil, nil).Once()
+
+		req := httptest.NewRequest("GET", "/api/v1/products/"+id.Hex(), nil)
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+}
+
+func TestGetProducts(t *testing.T) {
+	mockUseCase := new(MockProductUseCase)
+	router := newTestRouter(mockUseCase)
+
+	t.Run("Success", func(t *testing.T) {
+		expected := []domain.Product{{ID: primitive.NewObjectID(), Name: "Keyboard"}}
+		mockUseCase.On("GetProducts", mock.Anything).Return(expected, nil).Once()
+
+		req := httptest.NewRequest("GET", "/api/v1/products", nil)
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var response []domain.Product
+		err := json.Unmarshal(rr.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 1)
+		mockUseCase.AssertExpectations(t)
+	})
+}
+
+func TestUpdateProduct(t *testing.T) {
+	mockUseCase := new(MockProductUseCase)
+	router := newTestRouter(mockUseCase)
+
+	t.Run("Success", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockUseCase.On("UpdateProduct", mock.Anything, mock.MatchedBy(func(p *domain.Product) bool {
+			return p.ID == id && p.Price == money.New(12000, "USD") && !p.Active
+		})).Return(nil).Once()
+
+		req := httptest.NewRequest("PUT", "/api/v1/products/"+id.Hex(), bytes.NewBufferString(`{"name":"Keyboard","price":120,"active":false}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockUseCase.On("UpdateProduct", mock.Anything, mock.Anything).Return(domain.ErrProductNotFound).Once()
+
+		req := httptest.NewRequest("PUT", "/api/v1/products/"+id.Hex(), bytes.NewBufferString(`{"name":"Keyboard","price":120}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+}
+
+func TestDeleteProduct(t *testing.T) {
+	mockUseCase := new(MockProductUseCase)
+	router := newTestRouter(mockUseCase)
+
+	t.Run("Success", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockUseCase.On("DeleteProduct", mock.Anything, id).Return(nil).Once()
+
+		req := httptest.NewRequest("DELETE", "/api/v1/products/"+id.Hex(), nil)
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+}
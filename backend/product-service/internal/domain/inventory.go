@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const DefaultReservationTTL = 15 * time.Minute
+
+var (
+	ErrInsufficientStock   = errors.New("insufficient stock")
+	ErrReservationNotFound = errors.New("reservation not found")
+	// ErrReservationClosed is returned when a reservation can no longer move
+	// to the requested state, e.g. committing one that already expired.
+	ErrReservationClosed  = errors.New("reservation is no longer open")
+	ErrInvalidReservation = errors.New("reservation must have an order ID and at least one item with a positive quantity")
+	// ErrReservationExists is returned by ReservationRepository.Create when the
+	// order already has a reservation.
+	ErrReservationExists = errors.New("order already has a reservation")
+	ErrInvalidStock      = errors.New("stock on hand must not be negative")
+)
+
+// Stock is the inventory level of one product. Reserved units are held for
+// open reservations and cannot be reserved again.
+type Stock struct {
+	ProductID primitive.ObjectID `json:"product_id" bson:"_id"`
+	OnHand    int                `json:"on_hand" bson:"on_hand"`
+	Reserved  int                `json:"reserved" bson:"reserved"`
+	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+func (s Stock) Available() int {
+	return s.OnHand - s.Reserved
+}
+
+type ReservationStatus string
+
+const (
+	ReservationReserved  ReservationStatus = "reserved"
+	ReservationCommitted ReservationStatus = "committed"
+	ReservationReleased  ReservationStatus = "released"
+	ReservationExpired   ReservationStatus = "expired"
+)
+
+// Reservation holds stock for one order until it is committed (the units
+// leave the warehouse), released, or expires.
+type Reservation struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	OrderID   string             `json:"order_id" bson:"order_id"`
+	Items     []ReservationItem  `json:"items" bson:"items"`
+	Status    ReservationStatus  `json:"status" bson:"status"`
+	ExpiresAt time.Time          `json:"expires_at" bson:"expires_at"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+type ReservationItem struct {
+	ProductID primitive.ObjectID `json:"product_id" bson:"product_id"`
+	Quantity  int                `json:"quantity" bson:"quantity"`
+}
+
+// HoldsSameItems reports whether the reservation covers exactly items,
+// ignoring order and how quantities of one product are split across lines.
+func (r *Reservation) HoldsSameItems(items []ReservationItem) bool {
+	quantities := make(map[primitive.ObjectID]int)
+	for _, item := range r.Items {
+		quantities[item.ProductID] += item.Quantity
+	}
+	for _, item := range items {
+		quantities[item.ProductID] -= item.Quantity
+	}
+	for _, q := range quantities {
+		if q != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+type StockRepository interface {
+	// GetStock returns nil, nil for products that never had stock set.
+	GetStock(ctx context.Context, productID primitive.ObjectID) (*Stock, error)
+	SetOnHand(ctx context.Context, productID primitive.ObjectID, onHand int) (*Stock, error)
+	// Reserve atomically holds quantity units if that many are available,
+	// and returns ErrInsufficientStock otherwise.
+	Reserve(ctx context.Context, productID primitive.ObjectID, quantity int) error
+	// Unreserve gives held units back; Consume removes them from stock and
+	// Unconsume puts consumed units back, held again.
+	Unreserve(ctx context.Context, productID primitive.ObjectID, quantity int) error
+	Consume(ctx context.Context, productID primitive.ObjectID, quantity int) error
+	Unconsume(ctx context.Context, productID primitive.ObjectID, quantity int) error
+}
+
+type ReservationRepository interface {
+	Create(ctx context.Context, reservation *Reservation) error
+	// GetByOrderID returns nil, nil when the order has no reservation.
+	GetByOrderID(ctx context.Context, orderID string) (*Reservation, error)
+	// Replace swaps in new items and expiry and reopens the reservation,
+	// provided it is still in status from. Otherwise it returns
+	// ErrReservationClosed.
+	Replace(ctx context.Context, from ReservationStatus, reservation *Reservation) error
+	// UpdateStatus moves the reservation from one status to another and
+	// returns ErrReservationClosed if it is no longer in from.
+	UpdateStatus(ctx context.Context, orderID string, from, to ReservationStatus) error
+	// ListExpired returns open reservations whose expiry is before now.
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]Reservation, error)
+}
+
+type InventoryUseCase interface {
+	GetStock(ctx context.Context, productID primitive.ObjectID) (*Stock, error)
+	SetStock(ctx context.Context, productID primitive.ObjectID, onHand int) (*Stock, error)
+	GetReservation(ctx context.Context, orderID string) (*Reservation, error)
+	// Reserve holds stock for every item or for none. Reserving again for the
+	// same order with the same items returns the existing reservation; with
+	// different items it adjusts the reservation, unless it was committed.
+	Reserve(ctx context.Context, orderID string, items []ReservationItem, ttl time.Duration) (*Reservation, error)
+	Commit(ctx context.Context, orderID string) (*Reservation, error)
+	// Reopen undoes Commit, for a sale the caller could not complete: the
+	// units go back into stock, still held for the order.
+	Reopen(ctx context.Context, orderID string) (*Reservation, error)
+	Release(ctx context.Context, orderID string) (*Reservation, error)
+	ExpireReservations(ctx context.Context, now time.Time) (int, error)
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/yourusername/ecommerce/pkg/money"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const DefaultCurrency = "USD"
+
+var (
+	ErrProductNotFound = errors.New("product not found")
+	ErrInvalidPrice    = errors.New("price must be a non-negative amount in an ISO 4217 currency")
+)
+
+type Product struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name        string             `json:"name" bson:"name"`
+	Description string             `json:"description" bson:"description"`
+	Price       money.Money        `json:"price" bson:"price"`
+	SKU         string             `json:"sku" bson:"sku"`
+	Active      bool               `json:"active" bson:"active"`
+	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+type ProductRepository interface {
+	Create(ctx context.Context, product *Product) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*Product, error)
+	GetAll(ctx context.Context) ([]Product, error)
+	Update(ctx context.Context, product *Product) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+type ProductUseCase interface {
+	CreateProduct(ctx context.Context, product *Product) error
+	GetProduct(ctx context.Context, id primitive.ObjectID) (*Product, error)
+	GetProducts(ctx context.Context) ([]Product, error)
+	UpdateProduct(ctx context.Context, product *Product) error
+	DeleteProduct(ctx context.Context, id primitive.ObjectID) error
+}
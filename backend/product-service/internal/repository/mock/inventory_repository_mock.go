@@ -0,0 +1,85 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/ecommerce/product-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MockStockRepository struct {
+	mock.Mock
+}
+
+func (m *MockStockRepository) GetStock(ctx context.Context, productID primitive.ObjectID) (*domain.Stock, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Stock), args.Error(1)
+}
+
+func (m *MockStockRepository) SetOnHand(ctx context.Context, productID primitive.ObjectID, onHand int) (*domain.Stock, error) {
+	args := m.Called(ctx, productID, onHand)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Stock), args.Error(1)
+}
+
+func (m *MockStockRepository) Reserve(ctx context.Context, productID primitive.ObjectID, quantity int) error {
+	args := m.Called(ctx, productID, quantity)
+	return args.Error(0)
+}
+
+func (m *MockStockRepository) Unreserve(ctx context.Context, productID primitive.ObjectID, quantity int) error {
+	args := m.Called(ctx, productID, quantity)
+	return args.Error(0)
+}
+
+func (m *MockStockRepository) Consume(ctx context.Context, productID primitive.ObjectID, quantity int) error {
+	args := m.Called(ctx, productID, quantity)
+	return args.Error(0)
+}
+
+func (m *MockStockRepository) Unconsume(ctx context.Context, productID primitive.ObjectID, quantity int) error {
+	args := m.Called(ctx, productID, quantity)
+	return args.Error(0)
+}
+
+type MockReservationRepository struct {
+	mock.Mock
+}
+
+func (m *MockReservationRepository) Create(ctx context.Context, reservation *domain.Reservation) error {
+	args := m.Called(ctx, reservation)
+	return args.Error(0)
+}
+
+func (m *MockReservationRepository) GetByOrderID(ctx context.Context, orderID string) (*domain.Reservation, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Reservation), args.Error(1)
+}
+
+func (m *MockReservationRepository) Replace(ctx context.Context, from domain.ReservationStatus, reservation *domain.Reservation) error {
+	args := m.Called(ctx, from, reservation)
+	return args.Error(0)
+}
+
+func (m *MockReservationRepository) UpdateStatus(ctx context.Context, orderID string, from, to domain.ReservationStatus) error {
+	args := m.Called(ctx, orderID, from, to)
+	return args.Error(0)
+}
+
+func (m *MockReservationRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]domain.Reservation, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Reservation), args.Error(1)
+}
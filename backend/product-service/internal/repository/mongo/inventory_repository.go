@@ -0,0 +1,184 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/ecommerce/product-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoStockRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoStockRepository(collection *mongo.Collection) domain.StockRepository {
+	return &mongoStockRepository{
+		collection: collection,
+	}
+}
+
+func (r *mongoStockRepository) GetStock(ctx context.Context, productID primitive.ObjectID) (*domain.Stock, error) {
+	var stock domain.Stock
+	err := r.collection.FindOne(ctx, bson.M{"_id": productID}).Decode(&stock)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &stock, nil
+}
+
+func (r *mongoStockRepository) SetOnHand(ctx context.Context, productID primitive.ObjectID, onHand int) (*domain.Stock, error) {
+	update := bson.M{
+		"$set":         bson.M{"on_hand": onHand, "updated_at": time.Now()},
+		"$setOnInsert": bson.M{"reserved": 0},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var stock domain.Stock
+	if err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": productID}, update, opts).Decode(&stock); err != nil {
+		return nil, err
+	}
+	return &stock, nil
+}
+
+func (r *mongoStockRepository) Reserve(ctx context.Context, productID primitive.ObjectID, quantity int) error {
+	filter := bson.M{
+		"_id": productID,
+		"$expr": bson.M{
+			"$gte": bson.A{bson.M{"$subtract": bson.A{"$on_hand", "$reserved"}}, quantity},
+		},
+	}
+	result, err := r.collection.UpdateOne(ctx, filter, stockUpdate(0, quantity))
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrInsufficientStock
+	}
+	return nil
+}
+
+func (r *mongoStockRepository) Unreserve(ctx context.Context, productID primitive.ObjectID, quantity int) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": productID}, stockUpdate(0, -quantity))
+	return err
+}
+
+func (r *mongoStockRepository) Consume(ctx context.Context, productID primitive.ObjectID, quantity int) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": productID}, stockUpdate(-quantity, -quantity))
+	return err
+}
+
+func (r *mongoStockRepository) Unconsume(ctx context.Context, productID primitive.ObjectID, quantity int) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": productID}, stockUpdate(quantity, quantity))
+	return err
+}
+
+func stockUpdate(onHand, reserved int) bson.M {
+	return bson.M{
+		"$inc": bson.M{"on_hand": onHand, "reserved": reserved},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+}
+
+type mongoReservationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoReservationRepository(collection *mongo.Collection) domain.ReservationRepository {
+	return &mongoReservationRepository{
+		collection: collection,
+	}
+}
+
+// EnsureReservationIndexes creates the unique order index that keeps one
+// reservation per order and the index the expiry sweep scans.
+func EnsureReservationIndexes(ctx context.Context, collection *mongo.Collection) error {
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "order_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "expires_at", Value: 1}},
+		},
+	})
+	return err
+}
+
+func (r *mongoReservationRepository) Create(ctx context.Context, reservation *domain.Reservation) error {
+	result, err := r.collection.InsertOne(ctx, reservation)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrReservationExists
+		}
+		return err
+	}
+	if id, ok := result.InsertedID.(primitive.ObjectID); ok {
+		reservation.ID = id
+	}
+	return nil
+}
+
+func (r *mongoReservationRepository) GetByOrderID(ctx context.Context, orderID string) (*domain.Reservation, error) {
+	var reservation domain.Reservation
+	err := r.collection.FindOne(ctx, bson.M{"order_id": orderID}).Decode(&reservation)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+func (r *mongoReservationRepository) Replace(ctx context.Context, from domain.ReservationStatus, reservation *domain.Reservation) error {
+	update := bson.M{
+		"$set": bson.M{
+			"items":      reservation.Items,
+			"status":     reservation.Status,
+			"expires_at": reservation.ExpiresAt,
+			"updated_at": reservation.UpdatedAt,
+		},
+	}
+	result, err := r.collection.UpdateOne(ctx, bson.M{"order_id": reservation.OrderID, "status": from}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrReservationClosed
+	}
+	return nil
+}
+
+func (r *mongoReservationRepository) UpdateStatus(ctx context.Context, orderID string, from, to domain.ReservationStatus) error {
+	update := bson.M{"$set": bson.M{"status": to, "updated_at": time.Now()}}
+	result, err := r.collection.UpdateOne(ctx, bson.M{"order_id": orderID, "status": from}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrReservationClosed
+	}
+	return nil
+}
+
+func (r *mongoReservationRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]domain.Reservation, error) {
+	filter := bson.M{"status": domain.ReservationReserved, "expires_at": bson.M{"$lt": now}}
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetLimit(int64(limit)))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	reservations := []domain.Reservation{}
+	if err := cursor.All(ctx, &reservations); err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
@@ -0,0 +1,133 @@
+package mongo
+
+import (
+	"context"
+	"strings"
+
+	"github.com/yourusername/ecommerce/pkg/money"
+	"github.com/yourusername/ecommerce/product-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoProductRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoProductRepository(collection *mongo.Collection) domain.ProductRepository {
+	return &mongoProductRepository{
+		collection: collection,
+	}
+}
+
+func (r *mongoProductRepository) Create(ctx context.Context, product *domain.Product) error {
+	result, err := r.collection.InsertOne(ctx, product)
+	if err != nil {
+		return err
+	}
+	if id, ok := result.InsertedID.(primitive.ObjectID); ok {
+		product.ID = id
+	}
+	return nil
+}
+
+func (r *mongoProductRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.Product, error) {
+	var product domain.Product
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&product)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (r *mongoProductRepository) GetAll(ctx context.Context) ([]domain.Product, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	products := []domain.Product{}
+	if err := cursor.All(ctx, &products); err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+func (r *mongoProductRepository) Update(ctx context.Context, product *domain.Product) error {
+	update := bson.M{
+		"$set": bson.M{
+			"name":        product.Name,
+			"description": product.Description,
+			"price":       product.Price,
+			"sku":         product.SKU,
+			"active":      product.Active,
+			"updated_at":  product.UpdatedAt,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": product.ID}, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrProductNotFound
+	}
+
+	return nil
+}
+
+func (r *mongoProductRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return domain.ErrProductNotFound
+	}
+
+	return nil
+}
+
+// MigrateLegacyPrices rewrites products stored before prices carried a
+// currency: a float "price" plus an optional "currency" field become a single
+// Money subdocument. It is safe to run on every start.
+func MigrateLegacyPrices(ctx context.Context, collection *mongo.Collection) (int, error) {
+	cursor, err := collection.Find(ctx, bson.M{"price": bson.M{"$type": "number"}})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	migrated := 0
+	for cursor.Next(ctx) {
+		var legacy struct {
+			ID       primitive.ObjectID `bson:"_id"`
+			Price    float64            `bson:"price"`
+			Currency string             `bson:"currency"`
+		}
+		if err := cursor.Decode(&legacy); err != nil {
+			return migrated, err
+		}
+		currency := strings.ToUpper(legacy.Currency)
+		if currency == "" {
+			currency = money.LegacyCurrency
+		}
+		update := bson.M{
+			"$set":   bson.M{"price": money.FromFloat(legacy.Price, currency)},
+			"$unset": bson.M{"currency": ""},
+		}
+		if _, err := collection.UpdateOne(ctx, bson.M{"_id": legacy.ID}, update); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+	return migrated, cursor.Err()
+}
@@ -0,0 +1,282 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/yourusername/ecommerce/product-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const expiryBatchSize = 100
+
+type inventoryUseCase struct {
+	productRepo     domain.ProductRepository
+	stockRepo       domain.StockRepository
+	reservationRepo domain.ReservationRepository
+}
+
+func NewInventoryUseCase(productRepo domain.ProductRepository, stockRepo domain.StockRepository, reservationRepo domain.ReservationRepository) domain.InventoryUseCase {
+	return &inventoryUseCase{
+		productRepo:     productRepo,
+		stockRepo:       stockRepo,
+		reservationRepo: reservationRepo,
+	}
+}
+
+func (u *inventoryUseCase) GetStock(ctx context.Context, productID primitive.ObjectID) (*domain.Stock, error) {
+	stock, err := u.stockRepo.GetStock(ctx, productID)
+	if err != nil || stock != nil {
+		return stock, err
+	}
+
+	product, err := u.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, domain.ErrProductNotFound
+	}
+	return &domain.Stock{ProductID: productID}, nil
+}
+
+func (u *inventoryUseCase) SetStock(ctx context.Context, productID primitive.ObjectID, onHand int) (*domain.Stock, error) {
+	if onHand < 0 {
+		return nil, domain.ErrInvalidStock
+	}
+
+	product, err := u.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, domain.ErrProductNotFound
+	}
+	return u.stockRepo.SetOnHand(ctx, productID, onHand)
+}
+
+func (u *inventoryUseCase) GetReservation(ctx context.Context, orderID string) (*domain.Reservation, error) {
+	reservation, err := u.reservationRepo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if reservation == nil {
+		return nil, domain.ErrReservationNotFound
+	}
+	return reservation, nil
+}
+
+func (u *inventoryUseCase) Reserve(ctx context.Context, orderID string, items []domain.ReservationItem, ttl time.Duration) (*domain.Reservation, error) {
+	if orderID == "" || len(items) == 0 {
+		return nil, domain.ErrInvalidReservation
+	}
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return nil, domain.ErrInvalidReservation
+		}
+	}
+
+	existing, err := u.reservationRepo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.HoldsSameItems(items) &&
+		(existing.Status == domain.ReservationReserved || existing.Status == domain.ReservationCommitted) {
+		return existing, nil
+	}
+	if existing != nil && existing.Status == domain.ReservationCommitted {
+		return nil, domain.ErrReservationClosed
+	}
+
+	if err := u.reserveAll(ctx, items); err != nil {
+		if errors.Is(err, domain.ErrInsufficientStock) {
+			reservationOutcomes.WithLabelValues("rejected").Inc()
+		}
+		return nil, err
+	}
+
+	if ttl <= 0 {
+		ttl = domain.DefaultReservationTTL
+	}
+	now := time.Now()
+	reservation := &domain.Reservation{
+		OrderID:   orderID,
+		Items:     items,
+		Status:    domain.ReservationReserved,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if existing != nil {
+		// The order changed its items, or its old reservation lapsed: hold
+		// the new items first, then give back whatever the old one held.
+		reservation.ID = existing.ID
+		reservation.CreatedAt = existing.CreatedAt
+		if err := u.reservationRepo.Replace(ctx, existing.Status, reservation); err != nil {
+			u.unreserve(ctx, items)
+			return nil, err
+		}
+		if existing.Status == domain.ReservationReserved {
+			u.unreserve(ctx, existing.Items)
+		}
+		reservationOutcomes.WithLabelValues("reserved").Inc()
+		return reservation, nil
+	}
+
+	if err := u.reservationRepo.Create(ctx, reservation); err != nil {
+		u.unreserve(ctx, items)
+		if errors.Is(err, domain.ErrReservationExists) {
+			// A concurrent request for the same order won the race.
+			return u.GetReservation(ctx, orderID)
+		}
+		return nil, err
+	}
+	reservationOutcomes.WithLabelValues("reserved").Inc()
+	return reservation, nil
+}
+
+// reserveAll holds every item or, on the first failure, gives back the ones
+// it already held.
+func (u *inventoryUseCase) reserveAll(ctx context.Context, items []domain.ReservationItem) error {
+	for i, item := range items {
+		if err := u.stockRepo.Reserve(ctx, item.ProductID, item.Quantity); err != nil {
+			u.unreserve(ctx, items[:i])
+			if errors.Is(err, domain.ErrInsufficientStock) {
+				return fmt.Errorf("%w: product %s", err, item.ProductID.Hex())
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Commit turns held units into sold ones. Committing twice is a no-op.
+func (u *inventoryUseCase) Commit(ctx context.Context, orderID string) (*domain.Reservation, error) {
+	reservation, err := u.GetReservation(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case reservation.Status == domain.ReservationCommitted:
+		return reservation, nil
+	case reservation.Status != domain.ReservationReserved:
+		return nil, domain.ErrReservationClosed
+	case time.Now().After(reservation.ExpiresAt):
+		if err := u.expire(ctx, reservation); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrReservationClosed
+	}
+
+	if err := u.reservationRepo.UpdateStatus(ctx, orderID, domain.ReservationReserved, domain.ReservationCommitted); err != nil {
+		return nil, err
+	}
+	for _, item := range reservation.Items {
+		if err := u.stockRepo.Consume(ctx, item.ProductID, item.Quantity); err != nil {
+			return nil, err
+		}
+	}
+	reservation.Status = domain.ReservationCommitted
+	reservationOutcomes.WithLabelValues("committed").Inc()
+	return reservation, nil
+}
+
+// Reopen moves a committed reservation back to reserved and returns its
+// units to stock. Reopening a reservation that is still reserved is a no-op.
+func (u *inventoryUseCase) Reopen(ctx context.Context, orderID string) (*domain.Reservation, error) {
+	reservation, err := u.GetReservation(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch reservation.Status {
+	case domain.ReservationReserved:
+		return reservation, nil
+	case domain.ReservationReleased, domain.ReservationExpired:
+		return nil, domain.ErrReservationClosed
+	}
+
+	if err := u.reservationRepo.UpdateStatus(ctx, orderID, domain.ReservationCommitted, domain.ReservationReserved); err != nil {
+		return nil, err
+	}
+	for _, item := range reservation.Items {
+		if err := u.stockRepo.Unconsume(ctx, item.ProductID, item.Quantity); err != nil {
+			return nil, err
+		}
+	}
+	reservation.Status = domain.ReservationReserved
+	reservationOutcomes.WithLabelValues("reopened").Inc()
+	return reservation, nil
+}
+
+// Release gives held units back. Releasing a reservation that is already
+// released or expired is a no-op; a committed one cannot be released.
+func (u *inventoryUseCase) Release(ctx context.Context, orderID string) (*domain.Reservation, error) {
+	reservation, err := u.GetReservation(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch reservation.Status {
+	case domain.ReservationReleased, domain.ReservationExpired:
+		return reservation, nil
+	case domain.ReservationCommitted:
+		return nil, domain.ErrReservationClosed
+	}
+
+	if err := u.reservationRepo.UpdateStatus(ctx, orderID, domain.ReservationReserved, domain.ReservationReleased); err != nil {
+		return nil, err
+	}
+	u.unreserve(ctx, reservation.Items)
+	reservation.Status = domain.ReservationReleased
+	reservationOutcomes.WithLabelValues("released").Inc()
+	return reservation, nil
+}
+
+// ExpireReservations releases open reservations whose expiry has passed and
+// returns how many it expired.
+func (u *inventoryUseCase) ExpireReservations(ctx context.Context, now time.Time) (int, error) {
+	reservations, err := u.reservationRepo.ListExpired(ctx, now, expiryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for i := range reservations {
+		err := u.expire(ctx, &reservations[i])
+		if errors.Is(err, domain.ErrReservationClosed) {
+			// Committed or released since it was listed.
+			continue
+		}
+		if err != nil {
+			return expired, err
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+func (u *inventoryUseCase) expire(ctx context.Context, reservation *domain.Reservation) error {
+	if err := u.reservationRepo.UpdateStatus(ctx, reservation.OrderID, domain.ReservationReserved, domain.ReservationExpired); err != nil {
+		return err
+	}
+	u.unreserve(ctx, reservation.Items)
+	reservation.Status = domain.ReservationExpired
+	reservationOutcomes.WithLabelValues("expired").Inc()
+	return nil
+}
+
+// unreserve is best effort: a failure leaves units held, which is logged for
+// an operator to correct rather than failing the caller.
+func (u *inventoryUseCase) unreserve(ctx context.Context, items []domain.ReservationItem) {
+	for _, item := range items {
+		if err := u.stockRepo.Unreserve(ctx, item.ProductID, item.Quantity); err != nil {
+			slog.ErrorContext(ctx, "unreserve stock", "product_id", item.ProductID.Hex(), "quantity", item.Quantity, "error", err)
+		}
+	}
+}
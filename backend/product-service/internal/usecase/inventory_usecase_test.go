@@ -0,0 +1,311 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/ecommerce/product-service/internal/domain"
+	mockRepo "github.com/yourusername/ecommerce/product-service/internal/repository/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newTestInventory() (*mockRepo.MockProductRepository, *mockRepo.MockStockRepository, *mockRepo.MockReservationRepository, domain.InventoryUseCase) {
+	products := new(mockRepo.MockProductRepository)
+	stock := new(mockRepo.MockStockRepository)
+	reservations := new(mockRepo.MockReservationRepository)
+	return products, stock, reservations, NewInventoryUseCase(products, stock, reservations)
+}
+
+func TestSetStock(t *testing.T) {
+	products, stock, _, useCase := newTestInventory()
+
+	t.Run("Success", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		products.On("GetByID", mock.Anything, id).Return(&domain.Product{ID: id}, nil).Once()
+		stock.On("SetOnHand", mock.Anything, id, 10).Return(&domain.Stock{ProductID: id, OnHand: 10}, nil).Once()
+
+		result, err := useCase.SetStock(context.Background(), id, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 10, result.Available())
+		stock.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Product", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		products.On("GetByID", mock.Anything, id).Return(nil, nil).Once()
+
+		_, err := useCase.SetStock(context.Background(), id, 10)
+
+		assert.ErrorIs(t, err, domain.ErrProductNotFound)
+	})
+
+	t.Run("Negative", func(t *testing.T) {
+		_, err := useCase.SetStock(context.Background(), primitive.NewObjectID(), -1)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidStock)
+	})
+}
+
+func TestReserve(t *testing.T) {
+	_, stock, reservations, useCase := newTestInventory()
+	keyboard, mouse := primitive.NewObjectID(), primitive.NewObjectID()
+	items := []domain.ReservationItem{{ProductID: keyboard, Quantity: 2}, {ProductID: mouse, Quantity: 1}}
+
+	t.Run("Success", func(t *testing.T) {
+		reservations.On("GetByOrderID", mock.Anything, "order-1").Return(nil, nil).Once()
+		stock.On("Reserve", mock.Anything, keyboard, 2).Return(nil).Once()
+		stock.On("Reserve", mock.Anything, mouse, 1).Return(nil).Once()
+		reservations.On("Create", mock.Anything, mock.MatchedBy(func(r *domain.Reservation) bool {
+			return r.OrderID == "order-1" &&
+				r.Status == domain.ReservationReserved &&
+				r.ExpiresAt.Sub(r.CreatedAt) == time.Minute
+		})).Return(nil).Once()
+
+		reservation, err := useCase.Reserve(context.Background(), "order-1", items, time.Minute)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ReservationReserved, reservation.Status)
+		stock.AssertExpectations(t)
+		reservations.AssertExpectations(t)
+	})
+
+	t.Run("Insufficient Stock Rolls Back", func(t *testing.T) {
+		reservations.On("GetByOrderID", mock.Anything, "order-2").Return(nil, nil).Once()
+		stock.On("Reserve", mock.Anything, keyboard, 2).Return(nil).Once()
+		stock.On("Reserve", mock.Anything, mouse, 1).Return(domain.ErrInsufficientStock).Once()
+		stock.On("Unreserve", mock.Anything, keyboard, 2).Return(nil).Once()
+
+		reservation, err := useCase.Reserve(context.Background(), "order-2", items, 0)
+
+		assert.ErrorIs(t, err, domain.ErrInsufficientStock)
+		assert.Contains(t, err.Error(), mouse.Hex())
+		assert.Nil(t, reservation)
+		stock.AssertExpectations(t)
+	})
+
+	t.Run("Existing Reservation", func(t *testing.T) {
+		existing := &domain.Reservation{
+			OrderID: "order-3",
+			Items:   []domain.ReservationItem{{ProductID: mouse, Quantity: 1}, {ProductID: keyboard, Quantity: 2}},
+			Status:  domain.ReservationReserved,
+		}
+		reservations.On("GetByOrderID", mock.Anything, "order-3").Return(existing, nil).Once()
+
+		reservation, err := useCase.Reserve(context.Background(), "order-3", items, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, existing, reservation)
+	})
+
+	t.Run("Changed Items", func(t *testing.T) {
+		existing := &domain.Reservation{
+			ID:      primitive.NewObjectID(),
+			OrderID: "order-5",
+			Items:   []domain.ReservationItem{{ProductID: keyboard, Quantity: 5}},
+			Status:  domain.ReservationReserved,
+		}
+		reservations.On("GetByOrderID", mock.Anything, "order-5").Return(existing, nil).Once()
+		stock.On("Reserve", mock.Anything, keyboard, 2).Return(nil).Once()
+		stock.On("Reserve", mock.Anything, mouse, 1).Return(nil).Once()
+		reservations.On("Replace", mock.Anything, domain.ReservationReserved, mock.MatchedBy(func(r *domain.Reservation) bool {
+			return r.ID == existing.ID && len(r.Items) == 2 && r.Status == domain.ReservationReserved
+		})).Return(nil).Once()
+		stock.On("Unreserve", mock.Anything, keyboard, 5).Return(nil).Once()
+
+		reservation, err := useCase.Reserve(context.Background(), "order-5", items, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, items, reservation.Items)
+		stock.AssertExpectations(t)
+		reservations.AssertExpectations(t)
+	})
+
+	t.Run("Reopens Expired", func(t *testing.T) {
+		existing := &domain.Reservation{OrderID: "order-6", Items: items, Status: domain.ReservationExpired}
+		reservations.On("GetByOrderID", mock.Anything, "order-6").Return(existing, nil).Once()
+		stock.On("Reserve", mock.Anything, keyboard, 2).Return(nil).Once()
+		stock.On("Reserve", mock.Anything, mouse, 1).Return(nil).Once()
+		reservations.On("Replace", mock.Anything, domain.ReservationExpired, mock.Anything).Return(nil).Once()
+
+		reservation, err := useCase.Reserve(context.Background(), "order-6", items, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ReservationReserved, reservation.Status)
+		stock.AssertExpectations(t)
+		reservations.AssertExpectations(t)
+	})
+
+	t.Run("Committed With Different Items", func(t *testing.T) {
+		existing := &domain.Reservation{OrderID: "order-7", Items: items[:1], Status: domain.ReservationCommitted}
+		reservations.On("GetByOrderID", mock.Anything, "order-7").Return(existing, nil).Once()
+
+		_, err := useCase.Reserve(context.Background(), "order-7", items, 0)
+
+		assert.ErrorIs(t, err, domain.ErrReservationClosed)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := useCase.Reserve(context.Background(), "order-4", []domain.ReservationItem{{ProductID: keyboard}}, 0)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidReservation)
+	})
+}
+
+func TestCommitReservation(t *testing.T) {
+	_, stock, reservations, useCase := newTestInventory()
+	productID := primitive.NewObjectID()
+	items := []domain.ReservationItem{{ProductID: productID, Quantity: 2}}
+
+	t.Run("Success", func(t *testing.T) {
+		reservations.On("GetByOrderID", mock.Anything, "order-1").
+			Return(&domain.Reservation{OrderID: "order-1", Items: items, Status: domain.ReservationReserved, ExpiresAt: time.Now().Add(time.Minute)}, nil).Once()
+		reservations.On("UpdateStatus", mock.Anything, "order-1", domain.ReservationReserved, domain.ReservationCommitted).Return(nil).Once()
+		stock.On("Consume", mock.Anything, productID, 2).Return(nil).Once()
+
+		reservation, err := useCase.Commit(context.Background(), "order-1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ReservationCommitted, reservation.Status)
+		stock.AssertExpectations(t)
+		reservations.AssertExpectations(t)
+	})
+
+	t.Run("Already Committed", func(t *testing.T) {
+		reservations.On("GetByOrderID", mock.Anything, "order-2").
+			Return(&domain.Reservation{OrderID: "order-2", Status: domain.ReservationCommitted}, nil).Once()
+
+		_, err := useCase.Commit(context.Background(), "order-2")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Past Expiry", func(t *testing.T) {
+		reservations.On("GetByOrderID", mock.Anything, "order-3").
+			Return(&domain.Reservation{OrderID: "order-3", Items: items, Status: domain.ReservationReserved, ExpiresAt: time.Now().Add(-time.Second)}, nil).Once()
+		reservations.On("UpdateStatus", mock.Anything, "order-3", domain.ReservationReserved, domain.ReservationExpired).Return(nil).Once()
+		stock.On("Unreserve", mock.Anything, productID, 2).Return(nil).Once()
+
+		_, err := useCase.Commit(context.Background(), "order-3")
+
+		assert.ErrorIs(t, err, domain.ErrReservationClosed)
+		stock.AssertExpectations(t)
+	})
+
+	t.Run("Released", func(t *testing.T) {
+		reservations.On("GetByOrderID", mock.Anything, "order-4").
+			Return(&domain.Reservation{OrderID: "order-4", Status: domain.ReservationReleased}, nil).Once()
+
+		_, err := useCase.Commit(context.Background(), "order-4")
+
+		assert.ErrorIs(t, err, domain.ErrReservationClosed)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		reservations.On("GetByOrderID", mock.Anything, "order-5").Return(nil, nil).Once()
+
+		_, err := useCase.Commit(context.Background(), "order-5")
+
+		assert.ErrorIs(t, err, domain.ErrReservationNotFound)
+	})
+}
+
+func TestReopenReservation(t *testing.T) {
+	_, stock, reservations, useCase := newTestInventory()
+	productID := primitive.NewObjectID()
+	items := []domain.ReservationItem{{ProductID: productID, Quantity: 2}}
+
+	t.Run("Success", func(t *testing.T) {
+		reservations.On("GetByOrderID", mock.Anything, "order-1").
+			Return(&domain.Reservation{OrderID: "order-1", Items: items, Status: domain.ReservationCommitted}, nil).Once()
+		reservations.On("UpdateStatus", mock.Anything, "order-1", domain.ReservationCommitted, domain.ReservationReserved).Return(nil).Once()
+		stock.On("Unconsume", mock.Anything, productID, 2).Return(nil).Once()
+
+		reservation, err := useCase.Reopen(context.Background(), "order-1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ReservationReserved, reservation.Status)
+		stock.AssertExpectations(t)
+		reservations.AssertExpectations(t)
+	})
+
+	t.Run("Still Reserved", func(t *testing.T) {
+		reservations.On("GetByOrderID", mock.Anything, "order-2").
+			Return(&domain.Reservation{OrderID: "order-2", Items: items, Status: domain.ReservationReserved}, nil).Once()
+
+		_, err := useCase.Reopen(context.Background(), "order-2")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Released", func(t *testing.T) {
+		reservations.On("GetByOrderID", mock.Anything, "order-3").
+			Return(&domain.Reservation{OrderID: "order-3", Status: domain.ReservationReleased}, nil).Once()
+
+		_, err := useCase.Reopen(context.Background(), "order-3")
+
+		assert.ErrorIs(t, err, domain.ErrReservationClosed)
+	})
+}
+
+func TestReleaseReservation(t *testing.T) {
+	_, stock, reservations, useCase := newTestInventory()
+	productID := primitive.NewObjectID()
+	items := []domain.ReservationItem{{ProductID: productID, Quantity: 3}}
+
+	t.Run("Success", func(t *testing.T) {
+		reservations.On("GetByOrderID", mock.Anything, "order-1").
+			Return(&domain.Reservation{OrderID: "order-1", Items: items, Status: domain.ReservationReserved}, nil).Once()
+		reservations.On("UpdateStatus", mock.Anything, "order-1", domain.ReservationReserved, domain.ReservationReleased).Return(nil).Once()
+		stock.On("Unreserve", mock.Anything, productID, 3).Return(nil).Once()
+
+		reservation, err := useCase.Release(context.Background(), "order-1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ReservationReleased, reservation.Status)
+		stock.AssertExpectations(t)
+	})
+
+	t.Run("Already Expired", func(t *testing.T) {
+		reservations.On("GetByOrderID", mock.Anything, "order-2").
+			Return(&domain.Reservation{OrderID: "order-2", Status: domain.ReservationExpired}, nil).Once()
+
+		_, err := useCase.Release(context.Background(), "order-2")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Committed", func(t *testing.T) {
+		reservations.On("GetByOrderID", mock.Anything, "order-3").
+			Return(&domain.Reservation{OrderID: "order-3", Status: domain.ReservationCommitted}, nil).Once()
+
+		_, err := useCase.Release(context.Background(), "order-3")
+
+		assert.ErrorIs(t, err, domain.ErrReservationClosed)
+	})
+}
+
+func TestExpireReservations(t *testing.T) {
+	_, stock, reservations, useCase := newTestInventory()
+	productID := primitive.NewObjectID()
+	now := time.Now()
+	items := []domain.ReservationItem{{ProductID: productID, Quantity: 1}}
+
+	reservations.On("ListExpired", mock.Anything, now, expiryBatchSize).Return([]domain.Reservation{
+		{OrderID: "order-1", Items: items, Status: domain.ReservationReserved},
+		{OrderID: "order-2", Items: items, Status: domain.ReservationReserved},
+	}, nil).Once()
+	reservations.On("UpdateStatus", mock.Anything, "order-1", domain.ReservationReserved, domain.ReservationExpired).Return(nil).Once()
+	reservations.On("UpdateStatus", mock.Anything, "order-2", domain.ReservationReserved, domain.ReservationExpired).Return(domain.ErrReservationClosed).Once()
+	stock.On("Unreserve", mock.Anything, productID, 1).Return(nil).Once()
+
+	n, err := useCase.ExpireReservations(context.Background(), now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	stock.AssertExpectations(t)
+	reservations.AssertExpectations(t)
+}
@@ -0,0 +1,13 @@
+package usecase
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// reservationOutcomes counts what happened to stock reservations: reserved,
+// rejected for lack of stock, committed, released or expired.
+var reservationOutcomes = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "stock_reservations_total",
+	Help: "Stock reservation outcomes.",
+}, []string{"outcome"})
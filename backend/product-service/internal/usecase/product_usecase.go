@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/ecommerce/pkg/money"
+	"github.com/yourusername/ecommerce/product-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type productUseCase struct {
+	productRepo domain.ProductRepository
+}
+
+func NewProductUseCase(productRepo domain.ProductRepository) domain.ProductUseCase {
+	return &productUseCase{
+		productRepo: productRepo,
+	}
+}
+
+func (u *productUseCase) CreateProduct(ctx context.Context, product *domain.Product) error {
+	if err := validatePrice(product.Price); err != nil {
+		return err
+	}
+	product.CreatedAt = time.Now()
+	product.UpdatedAt = time.Now()
+	return u.productRepo.Create(ctx, product)
+}
+
+func (u *productUseCase) GetProduct(ctx context.Context, id primitive.ObjectID) (*domain.Product, error) {
+	return u.productRepo.GetByID(ctx, id)
+}
+
+func (u *productUseCase) GetProducts(ctx context.Context) ([]domain.Product, error) {
+	return u.productRepo.GetAll(ctx)
+}
+
+func (u *productUseCase) UpdateProduct(ctx context.Context, product *domain.Product) error {
+	if err := validatePrice(product.Price); err != nil {
+		return err
+	}
+	product.UpdatedAt = time.Now()
+	return u.productRepo.Update(ctx, product)
+}
+
+func (u *productUseCase) DeleteProduct(ctx context.Context, id primitive.ObjectID) error {
+	return u.productRepo.Delete(ctx, id)
+}
+
+func validatePrice(price money.Money) error {
+	if price.IsNegative() || !money.IsCurrency(price.Currency()) {
+		return domain.ErrInvalidPrice
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/ecommerce/pkg/money"
+	"github.com/yourusername/ecommerce/product-service/internal/domain"
+	mockRepo "github.com/yourusername/ecommerce/product-service/internal/repository/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCreateProduct(t *testing.T) {
+	mockRepo := new(mockRepo.MockProductRepository)
+	useCase := NewProductUseCase(mockRepo)
+
+	t.Run("Success", func(t *testing.T) {
+		product := &domain.Product{Name: "Keyboard", Price: money.New(9999, "THB"), SKU: "KB-1", Active: true}
+
+		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(p *domain.Product) bool {
+			return p.Name == "Keyboard" &&
+				p.Price == money.New(9999, "THB") &&
+				!p.CreatedAt.IsZero() &&
+				!p.UpdatedAt.IsZero()
+		})).Return(nil).Once()
+
+		err := useCase.CreateProduct(context.Background(), product)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Price", func(t *testing.T) {
+		for name, price := range map[string]money.Money{
+			"Negative":    money.New(-1, "USD"),
+			"No Currency": {},
+		} {
+			t.Run(name, func(t *testing.T) {
+				err := useCase.CreateProduct(context.Background(), &domain.Product{Name: "Mouse", Price: price})
+
+				assert.ErrorIs(t, err, domain.ErrInvalidPrice)
+			})
+		}
+	})
+
+	t.Run("Repository Error", func(t *testing.T) {
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(assert.AnError).Once()
+
+		err := useCase.CreateProduct(context.Background(), &domain.Product{Name: "Mouse", Price: money.New(1000, "USD")})
+
+		assert.Error(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestGetProduct(t *testing.T) {
+	mockRepo := new(mockRepo.MockProductRepository)
+	useCase := NewProductUseCase(mockRepo)
+
+	t.Run("Success", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		expected := &domain.Product{ID: id, Name: "Keyboard"}
+		mockRepo.On("GetByID", mock.Anything, id).Return(expected, nil).Once()
+
+		product, err := useCase.GetProduct(context.Background(), id)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, product)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockRepo.On("GetByID", mock.Anything, id).Return(nil, nil).Once()
+
+		product, err := useCase.GetProduct(context.Background(), id)
+
+		assert.NoError(t, err)
+		assert.Nil(t, product)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestGetProducts(t *testing.T) {
+	mockRepo := new(mockRepo.MockProductRepository)
+	useCase := NewProductUseCase(mockRepo)
+
+	t.Run("Success", func(t *testing.T) {
+		expected := []domain.Product{{ID: primitive.NewObjectID(), Name: "Keyboard"}}
+		mockRepo.On("GetAll", mock.Anything).Return(expected, nil).Once()
+
+		products, err := useCase.GetProducts(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, products)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Repository Error", func(t *testing.T) {
+		mockRepo.On("GetAll", mock.Anything).Return(nil, assert.AnError).Once()
+
+		products, err := useCase.GetProducts(context.Background())
+
+		assert.Error(t, err)
+		assert.Nil(t, products)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestUpdateProduct(t *testing.T) {
+	mockRepo := new(mockRepo.MockProductRepository)
+	useCase := NewProductUseCase(mockRepo)
+
+	t.Run("Success", func(t *testing.T) {
+		product := &domain.Product{ID: primitive.NewObjectID(), Name: "Keyboard", Price: money.New(12000, "USD")}
+		mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(p *domain.Product) bool {
+			return p.ID == product.ID && !p.UpdatedAt.IsZero()
+		})).Return(nil).Once()
+
+		err := useCase.UpdateProduct(context.Background(), product)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(domain.ErrProductNotFound).Once()
+
+		err := useCase.UpdateProduct(context.Background(), &domain.Product{ID: primitive.NewObjectID(), Price: money.New(12000, "USD")})
+
+		assert.ErrorIs(t, err, domain.ErrProductNotFound)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestDeleteProduct(t *testing.T) {
+	mockRepo := new(mockRepo.MockProductRepository)
+	useCase := NewProductUseCase(mockRepo)
+
+	t.Run("Success", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockRepo.On("Delete", mock.Anything, id).Return(nil).Once()
+
+		err := useCase.DeleteProduct(context.Background(), id)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Repository Error", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockRepo.On("Delete", mock.Anything, id).Return(assert.AnError).Once()
+
+		err := useCase.DeleteProduct(context.Background(), id)
+
+		assert.Error(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
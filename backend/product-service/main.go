@@ -1,36 +1,202 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"log"
+	"log/slog"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
+	"github.com/yourusername/ecommerce/pkg/config"
+	"github.com/yourusername/ecommerce/pkg/health"
+	"github.com/yourusername/ecommerce/pkg/logging"
+	"github.com/yourusername/ecommerce/pkg/metrics"
+	"github.com/yourusername/ecommerce/pkg/tracing"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+
+	productHttp "github.com/yourusername/ecommerce/product-service/internal/delivery/http"
+	productRepo "github.com/yourusername/ecommerce/product-service/internal/repository/mongo"
+	"github.com/yourusername/ecommerce/product-service/internal/usecase"
 )
 
 func main() {
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found")
+	cfg := Config{HTTP: config.HTTP{Port: "8082"}}
+	if err := config.Load(&cfg, os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		log.Fatal(err)
+	}
+	if _, err := logging.Setup(cfg.Log.Level, cfg.Log.Format); err != nil {
+		log.Fatal(err)
 	}
+	slog.Info("effective config", "config", config.LogValue(&cfg))
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8082"
+	if err := run(cfg); err != nil {
+		logging.Fatal(err)
 	}
+}
 
-	r := gin.Default()
+// run starts the service and blocks until it is stopped. Failures are
+// returned rather than exiting on the spot, so the deferred cleanup still
+// runs.
+func run(cfg Config) error {
+	// SIGTERM (docker stop, rolling deploys) and SIGINT start a graceful
+	// shutdown.
+	stopCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	// Health check
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status": "ok",
-		})
-	})
+	// Tracing
+	shutdownTracing, err := tracing.Setup(context.Background(), "product-service")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("tracing shutdown", "error", err)
+		}
+	}()
 
-	// Routes will be added here
-	
-	log.Printf("Product Service starting on port %s", port)
-	if err := r.Run(":" + port); err != nil {
-		log.Fatal(err)
+	// MongoDB connection
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Mongo.ConnectTimeout)
+	defer cancel()
+
+	monitor := tracing.MongoMonitor(metrics.MongoMonitor())
+	client, err := mongo.Connect(ctx, cfg.Mongo.ClientOptions().SetMonitor(monitor))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := client.Disconnect(ctx); err != nil {
+			slog.Error("mongo disconnect", "error", err)
+		}
+	}()
+
+	db := client.Database(cfg.Mongo.Database)
+	collection := db.Collection(cfg.ProductsCollection)
+	reservationCollection := db.Collection("reservations")
+	if err := productRepo.EnsureReservationIndexes(ctx, reservationCollection); err != nil {
+		return err
+	}
+	// Rewriting legacy prices may touch every product, so it is only bounded
+	// by a shutdown signal, not the connect timeout.
+	if n, err := productRepo.MigrateLegacyPrices(stopCtx, collection); err != nil {
+		return err
+	} else if n > 0 {
+		slog.Info("migrated legacy product prices", "count", n)
+	}
+
+	// Initialize layers
+	stockRepo := productRepo.NewMongoStockRepository(db.Collection("inventory"))
+	reservationRepo := productRepo.NewMongoReservationRepository(reservationCollection)
+	productRepo := productRepo.NewMongoProductRepository(collection)
+	productUseCase := usecase.NewProductUseCase(productRepo)
+	inventoryUseCase := usecase.NewInventoryUseCase(productRepo, stockRepo, reservationRepo)
+
+	// Release reservations whose orders never completed. The worker is
+	// stopped as part of the shutdown below, which nothing past this point
+	// returns before.
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		ticker := time.NewTicker(30 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-workersCtx.Done():
+				return
+			case now := <-ticker.C:
+				if n, err := inventoryUseCase.ExpireReservations(workersCtx, now); err != nil {
+					slog.Error("expire reservations", "error", err)
+				} else if n > 0 {
+					slog.Info("expired reservations", "count", n)
+				}
+			}
+		}
+	}()
+
+	// gin's own logger is replaced by the structured access log.
+	r := gin.New()
+	r.Use(gin.Recovery())
+	r.Use(otelgin.Middleware("product-service"))
+	r.Use(productHttp.LoggingMiddleware(cfg.Log.AccessLog))
+	r.Use(productHttp.MetricsMiddleware())
+
+	// Prometheus metrics
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	// Liveness and readiness probes; /health is kept for existing callers.
+	probes := health.NewChecker(2*time.Second, 5*time.Second)
+	probes.Add("mongodb", health.Mongo(client))
+	r.GET("/livez", gin.WrapH(probes.Livez()))
+	r.GET("/health", gin.WrapH(probes.Livez()))
+	r.GET("/readyz", gin.WrapH(probes.Readyz()))
+
+	// Register routes
+	productHttp.NewProductHandler(r, productUseCase)
+	productHttp.NewInventoryHandler(r, inventoryUseCase, productHttp.RequireRole(cfg.JWTSecret, productHttp.RoleAdmin, productHttp.RoleService))
+
+	// Start server
+	srv := cfg.HTTP.Server(r)
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("Product Service starting", "port", cfg.HTTP.Port)
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	var listenErr error
+	select {
+	case listenErr = <-serveErr:
+		// ListenAndServe only returns before Shutdown when it cannot serve
+		// at all, e.g. the port is taken.
+	case <-stopCtx.Done():
+		slog.Info("shutting down")
+
+		// Fail readiness first and keep serving for a moment, so Kong and
+		// other probers take the instance out of rotation before connections
+		// close.
+		probes.SetShuttingDown()
+		time.Sleep(cfg.ShutdownDelay)
+	}
+
+	// Stop accepting connections and let in-flight requests finish, then stop
+	// the expiry worker, all within the shutdown timeout.
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancelShutdown()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("http shutdown", "error", err)
+	}
+	stopWorkers()
+	if err := waitFor(shutdownCtx, &workers); err != nil {
+		slog.Error("background workers did not stop", "error", err)
+	}
+	return listenErr
+}
+
+// waitFor waits for wg, giving up when ctx is done.
+func waitFor(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 }